@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/accountctl"
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	// an interrupted import stops at its last checkpoint
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	status := accountctl.Run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	cancel()
+	os.Exit(status)
+}
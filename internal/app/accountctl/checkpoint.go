@@ -0,0 +1,105 @@
+package accountctl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+// checkpoint records how far an import got: every record up to Rows has been created or rejected.
+type checkpoint struct {
+	Rows int `json:"rows"`
+}
+
+// loadCheckpoint returns the checkpoint saved at path, or an empty one when there is none yet.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	c := &checkpoint{}
+	if path == "" {
+		return c, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// save replaces the checkpoint at path through a rename, so that an interrupted save leaves the
+// previous checkpoint in place.
+func (c *checkpoint) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".tmp", content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+var rejectsHeader = []string{"row", "error", "record"}
+
+// rejects is a CSV file of the records that were not created, with the reason why, appended to
+// by each run of an import so that it lists the rejects of the whole file.
+type rejects struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func openRejects(path string) (*rejects, error) {
+	if path == "" {
+		return &rejects{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	r := &rejects{file: file, writer: csv.NewWriter(file)}
+	info, err := file.Stat()
+	if err == nil && info.Size() == 0 {
+		err = r.writer.Write(rejectsHeader)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rejects) add(record *Record, reason string) error {
+	if r.writer == nil {
+		return nil
+	}
+	return r.writer.Write([]string{strconv.Itoa(record.Row), reason, record.Raw})
+}
+
+// flush is called before each checkpoint, so that the rejects of the rows it covers are on disk.
+func (r *rejects) flush() error {
+	if r.writer == nil {
+		return nil
+	}
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *rejects) close() error {
+	if r.file == nil {
+		return nil
+	}
+	if err := r.flush(); err != nil {
+		_ = r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,146 @@
+package accountctl
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+// requestTimeout bounds each request, as the client does by default.
+const requestTimeout = 30 * time.Second
+
+const usage = `usage: accountctl <command> [flags]
+
+commands:
+  import    create the accounts of a CSV or NDJSON file
+`
+
+// Run runs the command named by the first of args and returns the exit status of the tool.
+func Run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "import":
+		return runImport(ctx, args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		_, _ = fmt.Fprint(stdout, usage)
+		return 0
+	}
+	_, _ = fmt.Fprintf(stderr, "unknown command %q\n%s", args[0], usage)
+	return 2
+}
+
+func runImport(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "usage: accountctl import [flags] <file>")
+		flags.PrintDefaults()
+	}
+	baseURL := flags.String("url", envOr("ACCOUNT_API_URL", "http://localhost:8080"), "base url of the account API, or ACCOUNT_API_URL")
+	token := flags.String("token", os.Getenv("ACCOUNT_API_TOKEN"), "bearer token sent to the account API, or ACCOUNT_API_TOKEN")
+	options := ImportOptions{}
+	flags.StringVar(&options.Format, "format", "", "csv or ndjson, by default after the extension of the file")
+	flags.StringVar(&options.OrganisationID, "organisation-id", "", "organisation of the rows without organisation_id")
+	flags.IntVar(&options.BatchSize, "batch-size", DefaultBatchSize, "accounts sent per bulk create")
+	flags.IntVar(&options.Concurrency, "concurrency", DefaultConcurrency, "bulk creates sent at once")
+	flags.StringVar(&options.CheckpointPath, "checkpoint", "", "checkpoint file to resume from, <file>.checkpoint by default")
+	flags.StringVar(&options.RejectsPath, "rejects", "", "CSV file the rejected rows are appended to, <file>.rejects.csv by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+	if options.Format == "" {
+		options.Format = formatOf(path)
+	}
+	if options.CheckpointPath == "" {
+		options.CheckpointPath = path + ".checkpoint"
+	}
+	if options.RejectsPath == "" {
+		options.RejectsPath = path + ".rejects.csv"
+	}
+
+	client, err := newClient(*baseURL, *token)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 2
+	}
+	in, err := os.Open(path)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer in.Close()
+
+	summary, err := NewImporter(client, options).Import(ctx, in)
+	if summary != nil {
+		_, _ = fmt.Fprintf(stdout, "created %d, rejected %d, skipped %d already imported\n",
+			summary.Created, summary.Rejected, summary.Skipped)
+		if summary.Rejected > 0 {
+			_, _ = fmt.Fprintf(stdout, "rejected rows are listed in %s\n", options.RejectsPath)
+		}
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "import stopped: %v\nrun it again to resume from %s\n", err, options.CheckpointPath)
+		return 1
+	}
+	return 0
+}
+
+// formatOf tells the format of a file from its extension, taking CSV unless it is NDJSON.
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return accountexport.NDJSON
+	}
+	return accountexport.CSV
+}
+
+func envOr(name string, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newClient returns a client of the API that retries failed requests, sending the token if any.
+func newClient(baseURL string, token string) (*accountapi.Client, error) {
+	client, err := accountapi.NewClient(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	client.WithRetryPolicy(accountapi.NewRetryPolicy())
+	if token != "" {
+		client.WithHTTPClient(&http.Client{
+			Timeout:   requestTimeout,
+			Transport: &bearerTransport{token: token, underlying: http.DefaultTransport},
+		})
+	}
+	return client, nil
+}
+
+type bearerTransport struct {
+	token      string
+	underlying http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", t.token))
+	return t.underlying.RoundTrip(req)
+}
@@ -0,0 +1,311 @@
+package accountctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/go-openapi/strfmt"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultBatchSize   = 100
+	DefaultConcurrency = 4
+)
+
+// rowNamespace names the ids given to the records that have none, after their content, so that
+// a record sent again on resuming an import keeps its id.
+var rowNamespace = uuid.MustParse("1b3f1dd4-5bd4-4f21-9d71-1fdbaf0c5c0e")
+
+// ImportOptions are the settings of an import. Zero values take the defaults.
+type ImportOptions struct {
+	Format string
+	// OrganisationID is given to the records that have no organisation_id.
+	OrganisationID string
+	BatchSize      int
+	// Concurrency is the number of batches sent to the API at once.
+	Concurrency int
+	// CheckpointPath is where the progress of the import is saved, and read back from to resume
+	// it. There is no checkpoint when it is empty.
+	CheckpointPath string
+	// RejectsPath is the CSV file the records that are not created are appended to, with their
+	// error. They are only counted when it is empty.
+	RejectsPath string
+}
+
+// ImportSummary counts what became of the records read by an import.
+type ImportSummary struct {
+	// Skipped are the records a previous run got through, according to the checkpoint.
+	Skipped  int
+	Created  int
+	Rejected int
+}
+
+// Importer creates the accounts of CSV and NDJSON files through the bulk create of the API.
+//
+// Records are checked with the swagger models before being sent, and those that are invalid are
+// rejected without reaching the API. The others are sent in batches, several at once, and the
+// checkpoint moves past a batch once it and all the batches before it are done. An import that
+// fails, or is interrupted, resumes after the checkpoint when run again. The accounts of batches
+// that were in flight are then sent again. Those the API reports as duplicates are fetched, and
+// counted as created when they are the accounts of the file, which an earlier run created.
+type Importer struct {
+	client  *accountapi.Client
+	options ImportOptions
+}
+
+func NewImporter(client *accountapi.Client, options ImportOptions) *Importer {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+	return &Importer{client: client, options: options}
+}
+
+// batch is a run of consecutive records. Reasons holds why each record was rejected, and is
+// empty for those that were created.
+type batch struct {
+	seq     int
+	records []*Record
+	reasons []string
+	err     error
+}
+
+// Import creates the accounts of the file read from in. It only returns an error when the import
+// cannot go on, such as when the file cannot be read or the API cannot be reached: records that
+// cannot be created are rejected instead.
+func (i *Importer) Import(ctx context.Context, in io.Reader) (*ImportSummary, error) {
+	reader, err := NewReader(i.options.Format, in)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := loadCheckpoint(i.options.CheckpointPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read checkpoint: %v", err)
+	}
+	rejects, err := openRejects(i.options.RejectsPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open rejects file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	summary := &ImportSummary{}
+	batches := make(chan *batch)
+	var readErr error
+	go func() {
+		defer close(batches)
+		summary.Skipped, readErr = i.read(ctx, reader, checkpoint.Rows, batches)
+	}()
+
+	done := make(chan *batch)
+	var workers sync.WaitGroup
+	for n := 0; n < i.options.Concurrency; n++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for b := range batches {
+				b.err = i.send(ctx, b)
+				done <- b
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	err = i.collect(done, cancel, checkpoint, rejects, summary)
+	if closeErr := rejects.close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("cannot write rejects file: %v", closeErr)
+	}
+	if err == nil {
+		err = readErr
+	}
+	return summary, err
+}
+
+// read batches up the records after those the checkpoint covers, and returns how many it skipped.
+// It fails with the error of ctx when the import is stopped before every batch is handed over.
+func (i *Importer) read(ctx context.Context, reader Reader, skip int, batches chan<- *batch) (int, error) {
+	skipped := 0
+	current := &batch{}
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return skipped, err
+		}
+		if record.Row <= skip {
+			skipped++
+			continue
+		}
+		current.records = append(current.records, i.prepare(record))
+		if len(current.records) < i.options.BatchSize {
+			continue
+		}
+		select {
+		case batches <- current:
+		case <-ctx.Done():
+			return skipped, ctx.Err()
+		}
+		current = &batch{seq: current.seq + 1}
+	}
+	if len(current.records) > 0 {
+		select {
+		case batches <- current:
+		case <-ctx.Done():
+			return skipped, ctx.Err()
+		}
+	}
+	return skipped, nil
+}
+
+// prepare fills in what the record leaves to the import and checks it, setting Err when it is invalid.
+func (i *Importer) prepare(record *Record) *Record {
+	if record.Err != nil {
+		return record
+	}
+	account := &record.Account
+	account.Type = accountapi.ResourceTypeAccounts
+	if account.OrganisationID == "" {
+		account.OrganisationID = i.options.OrganisationID
+	}
+	if account.ID == "" {
+		account.ID = uuid.NewSHA1(rowNamespace, []byte(record.Raw)).String()
+	}
+	record.Err = validate(*account)
+	return record
+}
+
+// validate checks the account the way the API checks the body of a create.
+func validate(account accountapi.Account) error {
+	body, err := json.Marshal(accountapi.AccountData{Data: account})
+	if err != nil {
+		return err
+	}
+	creation := &models.AccountCreation{}
+	if err := json.Unmarshal(body, creation); err != nil {
+		return fmt.Errorf("invalid account: %v", err)
+	}
+	return creation.Validate(strfmt.NewFormats())
+}
+
+// send creates the valid accounts of the batch and sets the reasons of those that were rejected.
+func (i *Importer) send(ctx context.Context, b *batch) error {
+	b.reasons = make([]string, len(b.records))
+	var accounts []accountapi.Account
+	var sent []int
+	for n, record := range b.records {
+		if record.Err != nil {
+			b.reasons[n] = record.Err.Error()
+			continue
+		}
+		accounts = append(accounts, record.Account)
+		sent = append(sent, n)
+	}
+	if len(accounts) == 0 {
+		return nil
+	}
+	results, err := i.client.CreateMany(ctx, accounts, false)
+	if err != nil {
+		return err
+	}
+	if len(results) != len(accounts) {
+		return fmt.Errorf("bulk create answered for %d accounts out of %d", len(results), len(accounts))
+	}
+	for n, result := range results {
+		if result.Status == accountapi.BulkCreateStatusCreated {
+			continue
+		}
+		if result.Status == accountapi.BulkCreateStatusDuplicate {
+			created, err := i.createdBefore(ctx, accounts[n])
+			if err != nil {
+				return err
+			}
+			if created {
+				continue
+			}
+		}
+		reason := string(result.Status)
+		if result.Reason != "" {
+			reason = fmt.Sprintf("%s: %s", reason, result.Reason)
+		}
+		b.reasons[sent[n]] = reason
+	}
+	return nil
+}
+
+// createdBefore reports whether the account reported as a duplicate is the account that was sent,
+// rather than another one with its id or identifiers.
+func (i *Importer) createdBefore(ctx context.Context, account accountapi.Account) (bool, error) {
+	stored, err := i.client.Fetch(ctx, account.ID)
+	if errors.Is(err, accountapi.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return accountapi.SameAccount(account, *stored), nil
+}
+
+// collect takes in the batches as they are done and, in the order they were read, writes out their
+// rejects and moves the checkpoint past them. On the first failure it stops the import, then
+// drains the batches still in flight.
+func (i *Importer) collect(done <-chan *batch, stop func(), checkpoint *checkpoint, rejects *rejects, summary *ImportSummary) error {
+	var failure error
+	pending := map[int]*batch{}
+	next := 0
+	for b := range done {
+		if failure != nil {
+			continue
+		}
+		if b.err != nil {
+			failure = b.err
+			stop()
+			continue
+		}
+		pending[b.seq] = b
+		for ; pending[next] != nil; next++ {
+			if err := i.complete(pending[next], checkpoint, rejects, summary); err != nil {
+				failure = err
+				stop()
+				break
+			}
+			delete(pending, next)
+		}
+	}
+	return failure
+}
+
+func (i *Importer) complete(b *batch, checkpoint *checkpoint, rejects *rejects, summary *ImportSummary) error {
+	for n, reason := range b.reasons {
+		if reason == "" {
+			summary.Created++
+			continue
+		}
+		summary.Rejected++
+		if err := rejects.add(b.records[n], reason); err != nil {
+			return fmt.Errorf("cannot write rejects file: %v", err)
+		}
+	}
+	if err := rejects.flush(); err != nil {
+		return fmt.Errorf("cannot write rejects file: %v", err)
+	}
+	checkpoint.Rows = b.records[len(b.records)-1].Row
+	if err := checkpoint.save(i.options.CheckpointPath); err != nil {
+		return fmt.Errorf("cannot save checkpoint: %v", err)
+	}
+	return nil
+}
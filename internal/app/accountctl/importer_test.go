@@ -0,0 +1,281 @@
+package accountctl
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/stretchr/testify/assert"
+)
+
+const organisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+
+// fakeBulkServer creates the accounts it is sent, reporting those already created as duplicates,
+// and answers fetches of the accounts it created. Once failAfter bulk creates are answered, it
+// answers every other one with 500.
+type fakeBulkServer struct {
+	mu        sync.Mutex
+	created   map[string]accountapi.Account
+	requests  int
+	failAfter int
+}
+
+func newFakeBulkServer(t *testing.T, failAfter int) (*fakeBulkServer, *accountapi.Client) {
+	fake := &fakeBulkServer{created: map[string]accountapi.Account{}, failAfter: failAfter}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			account, ok := fake.created[strings.TrimPrefix(r.URL.Path, "/v1/organisation/accounts/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(accountapi.AccountData{Data: account})
+			return
+		}
+		assert.Equal(t, "/v1/organisation/accounts/bulk", r.URL.Path)
+		var body []accountapi.AccountData
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.requests++
+		if fake.failAfter > 0 && fake.requests > fake.failAfter {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		results := make([]accountapi.BulkCreateResult, len(body))
+		for i, account := range body {
+			results[i] = accountapi.BulkCreateResult{ID: account.Data.ID, Status: accountapi.BulkCreateStatusCreated}
+			if _, ok := fake.created[account.Data.ID]; ok {
+				results[i].Status = accountapi.BulkCreateStatusDuplicate
+				results[i].Reason = "account already exists"
+				continue
+			}
+			fake.created[account.Data.ID] = account.Data
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": results})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := accountapi.NewClient(server.URL)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return fake, client
+}
+
+// csvFile returns a file of count valid accounts, with the rows given by invalid swapped for
+// accounts without a country.
+func csvFile(count int, invalid ...int) string {
+	var b strings.Builder
+	b.WriteString("country,bank_id,bank_id_code,account_number,bank_account_name\n")
+	for row := 1; row <= count; row++ {
+		country := "GB"
+		for _, i := range invalid {
+			if i == row {
+				country = ""
+			}
+		}
+		_, _ = fmt.Fprintf(&b, "%s,400300,GBDSC,%08d,Holder %d\n", country, row, row)
+	}
+	return b.String()
+}
+
+func importOptions(t *testing.T) ImportOptions {
+	dir := t.TempDir()
+	return ImportOptions{
+		Format:         accountexport.CSV,
+		OrganisationID: organisationID,
+		BatchSize:      3,
+		Concurrency:    2,
+		CheckpointPath: filepath.Join(dir, "accounts.csv.checkpoint"),
+		RejectsPath:    filepath.Join(dir, "accounts.csv.rejects.csv"),
+	}
+}
+
+func readRejects(t *testing.T, path string) [][]string {
+	file, err := os.Open(path)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return rows
+}
+
+func TestImporter_CreatesTheAccountsAndRejectsTheInvalidRows(t *testing.T) {
+	fake, client := newFakeBulkServer(t, 0)
+	options := importOptions(t)
+
+	summary, err := NewImporter(client, options).Import(context.Background(), strings.NewReader(csvFile(10, 4)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportSummary{Created: 9, Rejected: 1}, summary)
+	assert.Len(t, fake.created, 9)
+	for _, account := range fake.created {
+		assert.Equal(t, organisationID, account.OrganisationID)
+		assert.Equal(t, accountapi.ResourceTypeAccounts, account.Type)
+		assert.NotEmpty(t, account.ID)
+	}
+	rejects := readRejects(t, options.RejectsPath)
+	if assert.Len(t, rejects, 2) {
+		assert.Equal(t, rejectsHeader, rejects[0])
+		assert.Equal(t, "4", rejects[1][0])
+		assert.Contains(t, rejects[1][1], "country")
+		assert.Equal(t, ",400300,GBDSC,00000004,Holder 4", rejects[1][2])
+	}
+	checkpoint, err := loadCheckpoint(options.CheckpointPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, checkpoint.Rows)
+}
+
+func TestImporter_RejectsTheAccountsTheAPIDoesNotCreate(t *testing.T) {
+	_, client := newFakeBulkServer(t, 0)
+	options := importOptions(t)
+	options.CheckpointPath = ""
+	file := "id,country,bank_id,bank_id_code,account_number,bank_account_name\n" +
+		"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc,GB,400300,GBDSC,00000001,Holder 1\n" +
+		"7d9f5f0c-3c8f-4b0e-9d44-3bb4c2b5c8a1,GB,400300,GBDSC,00000002,Holder 2\n" +
+		"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc,GB,400300,GBDSC,00000003,Holder 3\n"
+
+	summary, err := NewImporter(client, options).Import(context.Background(), strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportSummary{Created: 2, Rejected: 1}, summary)
+	rejects := readRejects(t, options.RejectsPath)
+	if assert.Len(t, rejects, 2) {
+		assert.Equal(t, []string{"3", "duplicate: account already exists", "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc,GB,400300,GBDSC,00000003,Holder 3"}, rejects[1])
+	}
+}
+
+func TestImporter_ResumesAfterTheCheckpoint(t *testing.T) {
+	fake, client := newFakeBulkServer(t, 2)
+	options := importOptions(t)
+	options.Concurrency = 1
+	file := csvFile(9, 2)
+
+	summary, err := NewImporter(client, options).Import(context.Background(), strings.NewReader(file))
+
+	assert.Error(t, err)
+	assert.Equal(t, &ImportSummary{Created: 5, Rejected: 1}, summary)
+	checkpoint, err := loadCheckpoint(options.CheckpointPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, checkpoint.Rows)
+
+	fake.failAfter = 0
+	summary, err = NewImporter(client, options).Import(context.Background(), strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportSummary{Skipped: 6, Created: 3}, summary)
+	assert.Len(t, fake.created, 8)
+	assert.Len(t, readRejects(t, options.RejectsPath), 2)
+}
+
+func TestImporter_CountsTheAccountsAnEarlierRunCreatedAsCreated(t *testing.T) {
+	fake, client := newFakeBulkServer(t, 0)
+	options := importOptions(t)
+	options.CheckpointPath = ""
+	file := csvFile(4)
+
+	_, err := NewImporter(client, options).Import(context.Background(), strings.NewReader(file))
+	assert.NoError(t, err)
+	summary, err := NewImporter(client, options).Import(context.Background(), strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportSummary{Created: 4}, summary)
+	assert.Len(t, fake.created, 4, "rows without an id keep theirs across runs")
+	assert.Len(t, readRejects(t, options.RejectsPath), 1, "only the header")
+}
+
+func TestImporter_FailsWhenCancelledMidImport(t *testing.T) {
+	fake, client := newFakeBulkServer(t, 0)
+	options := importOptions(t)
+	options.Concurrency = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in, file := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(file, csvFile(4))
+		for rows := 0; rows < 3; {
+			time.Sleep(10 * time.Millisecond)
+			if checkpoint, err := loadCheckpoint(options.CheckpointPath); err == nil {
+				rows = checkpoint.Rows
+			}
+		}
+		cancel()
+		_ = file.Close()
+	}()
+
+	summary, err := NewImporter(client, options).Import(ctx, in)
+
+	assert.Error(t, err)
+	assert.Equal(t, &ImportSummary{Created: 3}, summary)
+	assert.Len(t, fake.created, 3)
+}
+
+func TestImporter_ReadsNDJSON(t *testing.T) {
+	fake, client := newFakeBulkServer(t, 0)
+	options := importOptions(t)
+	options.Format = accountexport.NDJSON
+	file := `{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc","organisation_id":"` + organisationID + `","attributes":{"country":"GB","bank_id":"400300"}}` + "\n" +
+		`{"attributes":{"country":"gb"}}` + "\n"
+
+	summary, err := NewImporter(client, options).Import(context.Background(), strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportSummary{Created: 1, Rejected: 1}, summary)
+	assert.Contains(t, fake.created, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+}
+
+func TestRun_Import(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": []accountapi.BulkCreateResult{
+			{Status: accountapi.BulkCreateStatusCreated},
+			{Status: accountapi.BulkCreateStatusCreated},
+		}})
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "accounts.csv")
+	if !assert.NoError(t, ioutil.WriteFile(path, []byte(csvFile(3, 2)), 0644)) {
+		t.FailNow()
+	}
+	var stdout, stderr strings.Builder
+
+	status := Run(context.Background(), []string{"import", "-url", server.URL, "-token", "secret", "-organisation-id", organisationID, path}, &stdout, &stderr)
+
+	assert.Equal(t, 0, status, stderr.String())
+	assert.Equal(t, "created 2, rejected 1, skipped 0 already imported\nrejected rows are listed in "+path+".rejects.csv\n", stdout.String())
+	assert.FileExists(t, path+".checkpoint")
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	var stdout, stderr strings.Builder
+
+	status := Run(context.Background(), []string{"export"}, &stdout, &stderr)
+
+	assert.Equal(t, 2, status)
+	assert.Contains(t, stderr.String(), `unknown command "export"`)
+}
@@ -0,0 +1,200 @@
+// Package accountctl holds the commands of accountctl, the command line tool of the account API.
+package accountctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+// maxLineSize bounds the length of an NDJSON line.
+const maxLineSize = 1024 * 1024
+
+// Record is an account read from an import file, along with where it came from. Err is set when
+// the row could not be read as an account, which rejects the row rather than the whole file.
+type Record struct {
+	// Row counts the records of the file from 1, leaving out the CSV header and blank NDJSON lines.
+	Row     int
+	Raw     string
+	Account accountapi.Account
+	Err     error
+}
+
+// Reader reads the records of an import file one at a time. Next returns io.EOF after the last one.
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// NewReader returns a reader of the format, which is CSV or NDJSON, the formats of the export.
+func NewReader(format string, in io.Reader) (Reader, error) {
+	switch format {
+	case accountexport.CSV:
+		return newCSVReader(in)
+	case accountexport.NDJSON:
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	}
+	return nil, fmt.Errorf("format must be %s or %s", accountexport.CSV, accountexport.NDJSON)
+}
+
+type column func(account *accountapi.Account, value string) error
+
+// columns map the columns of a CSV import onto accounts. They are those of the CSV export, so that
+// an export can be imported again. The columns the API sets itself are read but ignored.
+var columns = map[string]column{
+	"id":              func(a *accountapi.Account, v string) error { a.ID = v; return nil },
+	"organisation_id": func(a *accountapi.Account, v string) error { a.OrganisationID = v; return nil },
+	"version":         ignored,
+	"created_on":      ignored,
+	"modified_on":     ignored,
+	"locked":          ignored,
+	"deleted":         ignored,
+	"country":         func(a *accountapi.Account, v string) error { a.Attributes.Country = v; return nil },
+	"base_currency":   func(a *accountapi.Account, v string) error { a.Attributes.BaseCurrency = v; return nil },
+	"bank_id":         func(a *accountapi.Account, v string) error { a.Attributes.BankID = v; return nil },
+	"bank_id_code":    func(a *accountapi.Account, v string) error { a.Attributes.BankIDCode = v; return nil },
+	"account_number":  func(a *accountapi.Account, v string) error { a.Attributes.AccountNumber = v; return nil },
+	"bic":             func(a *accountapi.Account, v string) error { a.Attributes.Bic = v; return nil },
+	"iban":            func(a *accountapi.Account, v string) error { a.Attributes.IBAN = v; return nil },
+	"customer_id":     func(a *accountapi.Account, v string) error { a.Attributes.CustomerID = v; return nil },
+	"title":           func(a *accountapi.Account, v string) error { a.Attributes.Title = v; return nil },
+	"first_name":      func(a *accountapi.Account, v string) error { a.Attributes.FirstName = v; return nil },
+	"bank_account_name": func(a *accountapi.Account, v string) error {
+		a.Attributes.BankAccountName = v
+		return nil
+	},
+	"alternative_bank_account_names": func(a *accountapi.Account, v string) error {
+		if v != "" {
+			a.Attributes.AlternativeBankAccountNames = strings.Split(v, accountexport.NameSeparator)
+		}
+		return nil
+	},
+	"account_classification": func(a *accountapi.Account, v string) error {
+		a.Attributes.AccountClassification = accountapi.AccountClassification(v)
+		return nil
+	},
+	"joint_account": func(a *accountapi.Account, v string) error {
+		return parseBool("joint_account", v, &a.Attributes.JointAccount)
+	},
+	"account_matching_opt_out": func(a *accountapi.Account, v string) error {
+		return parseBool("account_matching_opt_out", v, &a.Attributes.AccountMatchingOptOut)
+	},
+	"secondary_identification": func(a *accountapi.Account, v string) error {
+		a.Attributes.SecondaryIdentification = v
+		return nil
+	},
+}
+
+func ignored(*accountapi.Account, string) error {
+	return nil
+}
+
+// parseBool leaves the flag unset when the value is empty.
+func parseBool(name string, value string, flag *bool) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false, not %q", name, value)
+	}
+	*flag = parsed
+	return nil
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns []column
+	rows    int
+}
+
+// newCSVReader reads the header, whose columns must all be known, in any order.
+func newCSVReader(in io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("missing header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid header row: %v", err)
+	}
+	r := &csvReader{reader: reader}
+	for _, name := range header {
+		c, ok := columns[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		r.columns = append(r.columns, c)
+	}
+	return r, nil
+}
+
+func (r *csvReader) Next() (*Record, error) {
+	fields, err := r.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	r.rows++
+	record := &Record{Row: r.rows}
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); !ok {
+			return nil, err
+		}
+		record.Err = err
+		return record, nil
+	}
+	record.Raw = encodeCSV(fields)
+	if len(fields) != len(r.columns) {
+		record.Err = fmt.Errorf("row has %d columns, the header %d", len(fields), len(r.columns))
+		return record, nil
+	}
+	for i, c := range r.columns {
+		if err := c(&record.Account, fields[i]); err != nil {
+			record.Err = err
+			return record, nil
+		}
+	}
+	return record, nil
+}
+
+func encodeCSV(fields []string) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	rows    int
+}
+
+func (r *ndjsonReader) Next() (*Record, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		r.rows++
+		record := &Record{Row: r.rows, Raw: line}
+		if err := json.Unmarshal([]byte(line), &record.Account); err != nil {
+			record.Err = fmt.Errorf("invalid account: %v", err)
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
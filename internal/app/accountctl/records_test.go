@@ -0,0 +1,89 @@
+package accountctl
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, format string, content string) []*Record {
+	reader, err := NewReader(format, strings.NewReader(content))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVReader_MapsColumnsOntoAccounts(t *testing.T) {
+	records := readAll(t, accountexport.CSV, "bank_account_name,country,bank_id,alternative_bank_account_names,joint_account,account_matching_opt_out\n"+
+		"Samantha Holder,GB,400300,Sam Holder;S Holder,true,\n")
+
+	if !assert.Len(t, records, 1) {
+		return
+	}
+	assert.NoError(t, records[0].Err)
+	assert.Equal(t, 1, records[0].Row)
+	assert.Equal(t, "Samantha Holder,GB,400300,Sam Holder;S Holder,true,", records[0].Raw)
+	assert.Equal(t, accountapi.AccountAttributes{
+		Country:                     "GB",
+		BankID:                      "400300",
+		BankAccountName:             "Samantha Holder",
+		AlternativeBankAccountNames: []string{"Sam Holder", "S Holder"},
+		JointAccount:                true,
+	}, records[0].Account.Attributes)
+}
+
+func TestCSVReader_ReadsEveryColumnOfTheExport(t *testing.T) {
+	for _, name := range accountexport.Columns() {
+		_, ok := columns[name]
+		assert.True(t, ok, name)
+	}
+}
+
+func TestCSVReader_RejectsUnknownColumns(t *testing.T) {
+	_, err := NewReader(accountexport.CSV, strings.NewReader("country,sort_code\nGB,400300\n"))
+
+	assert.EqualError(t, err, `unknown column "sort_code"`)
+}
+
+func TestCSVReader_RejectsRowsAloneWhenTheyCannotBeRead(t *testing.T) {
+	records := readAll(t, accountexport.CSV, "country,joint_account\nGB,yes\nGB\nFR,false\n")
+
+	if !assert.Len(t, records, 3) {
+		return
+	}
+	assert.EqualError(t, records[0].Err, `joint_account must be true or false, not "yes"`)
+	assert.EqualError(t, records[1].Err, "row has 1 columns, the header 2")
+	assert.NoError(t, records[2].Err)
+	assert.Equal(t, "FR", records[2].Account.Attributes.Country)
+	assert.Equal(t, 3, records[2].Row)
+}
+
+func TestNDJSONReader_ReadsAnAccountPerLine(t *testing.T) {
+	records := readAll(t, accountexport.NDJSON, `{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc","version":3,"attributes":{"country":"GB"}}`+"\n\n"+
+		"{not json}\n"+
+		`{"attributes":{"country":"FR","customer_id":"C-1"}}`+"\n")
+
+	if !assert.Len(t, records, 3) {
+		return
+	}
+	assert.Equal(t, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", records[0].Account.ID)
+	assert.Equal(t, "GB", records[0].Account.Attributes.Country)
+	assert.Error(t, records[1].Err)
+	assert.Equal(t, 3, records[2].Row)
+	assert.Equal(t, "C-1", records[2].Account.Attributes.CustomerID)
+}
@@ -0,0 +1,124 @@
+package interview_accountapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type accountBulkStage struct {
+	t              *testing.T
+	organisationId uuid.UUID
+	client         *accountapi.Client
+	accounts       []accountapi.Account
+	results        []accountapi.BulkCreateResult
+	error          error
+}
+
+func AccountBulkTest(t *testing.T) (*accountBulkStage, *accountBulkStage, *accountBulkStage) {
+	organisationId := uuid.New()
+	stage := &accountBulkStage{
+		t:              t,
+		organisationId: organisationId,
+		client: newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort),
+			newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))),
+	}
+	return stage, stage, stage
+}
+
+func (s *accountBulkStage) and() *accountBulkStage {
+	return s
+}
+
+func (s *accountBulkStage) accounts_to_create(count int) *accountBulkStage {
+	for i := 0; i < count; i++ {
+		// numbered on from the accounts so far, as account numbers are unique in an organisation
+		s.accounts = append(s.accounts, newTestAccount(s.organisationId.String(), fmt.Sprintf("3%07d", len(s.accounts)), "400300"))
+	}
+	return s
+}
+
+func (s *accountBulkStage) an_invalid_account() *accountBulkStage {
+	account := newTestAccount(s.organisationId.String(), "39999999", "400300")
+	account.Attributes.BankID = "4003"
+	s.accounts = append(s.accounts, account)
+	return s
+}
+
+func (s *accountBulkStage) an_account_with_the_number_of_the_first() *accountBulkStage {
+	s.accounts = append(s.accounts, newTestAccount(s.organisationId.String(), s.accounts[0].Attributes.AccountNumber, "400300"))
+	return s
+}
+
+func (s *accountBulkStage) an_account_without_a_number() *accountBulkStage {
+	s.accounts = append(s.accounts, newTestAccount(s.organisationId.String(), "", "400300"))
+	return s
+}
+
+func (s *accountBulkStage) an_account_of_another_organisation() *accountBulkStage {
+	s.accounts = append(s.accounts, newTestAccount(uuid.New().String(), "38888888", "400300"))
+	return s
+}
+
+func (s *accountBulkStage) creating_the_accounts() *accountBulkStage {
+	s.results, s.error = s.client.CreateMany(context.Background(), s.accounts, false)
+	return s
+}
+
+func (s *accountBulkStage) creating_the_accounts_atomically() *accountBulkStage {
+	s.results, s.error = s.client.CreateMany(context.Background(), s.accounts, true)
+	return s
+}
+
+func (s *accountBulkStage) no_error_is_returned() *accountBulkStage {
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+// the_results_are checks the status of each account, in the order they were given. The statuses
+// of accounts beyond those given are expected to be the last one.
+func (s *accountBulkStage) the_results_are(statuses ...accountapi.BulkCreateStatus) *accountBulkStage {
+	if !assert.Len(s.t, s.results, len(s.accounts)) {
+		return s
+	}
+	for i, result := range s.results {
+		expected := statuses[len(statuses)-1]
+		if i < len(statuses) {
+			expected = statuses[i]
+		}
+		assert.Equal(s.t, s.accounts[i].ID, result.ID)
+		assert.Equal(s.t, expected, result.Status, "account %d: %s", i, result.Reason)
+		if expected != accountapi.BulkCreateStatusCreated {
+			assert.NotEmpty(s.t, result.Reason, "account %d", i)
+		}
+	}
+	return s
+}
+
+// only_the_created_accounts_exist fetches each account, which only those reported as created can be.
+func (s *accountBulkStage) only_the_created_accounts_exist() *accountBulkStage {
+	for i, result := range s.results {
+		if result.Status == accountapi.BulkCreateStatusForbidden {
+			continue
+		}
+		_, err := s.client.Fetch(context.Background(), s.accounts[i].ID)
+		if result.Status == accountapi.BulkCreateStatusCreated {
+			assert.NoError(s.t, err, "account %d", i)
+		} else {
+			assert.True(s.t, errors.Is(err, accountapi.ErrNotFound), "account %d: expected not found but got %v", i, err)
+		}
+	}
+	return s
+}
+
+func (s *accountBulkStage) the_error_is(expected error) *accountBulkStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+	return s
+}
@@ -0,0 +1,128 @@
+package interview_accountapi
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_Bulk_CreateAccounts(t *testing.T) {
+	given, when, then := AccountBulkTest(t)
+
+	given.
+		accounts_to_create(3)
+
+	when.
+		creating_the_accounts()
+
+	then.
+		no_error_is_returned().and().
+		the_results_are(accountapi.BulkCreateStatusCreated).and().
+		only_the_created_accounts_exist()
+}
+
+func TestAcc_Bulk_CreateMoreAccountsThanABatch(t *testing.T) {
+	given, when, then := AccountBulkTest(t)
+
+	given.
+		accounts_to_create(settings.BulkCreateBatchSize + 1)
+
+	when.
+		creating_the_accounts()
+
+	then.
+		no_error_is_returned().and().
+		the_results_are(accountapi.BulkCreateStatusCreated)
+}
+
+func TestAcc_Bulk_ReportsEachAccountThatCannotBeCreated(t *testing.T) {
+	given, when, then := AccountBulkTest(t)
+
+	given.
+		accounts_to_create(1).and().
+		an_invalid_account().and().
+		an_account_with_the_number_of_the_first().and().
+		an_account_of_another_organisation().and().
+		an_account_without_a_number()
+
+	when.
+		creating_the_accounts()
+
+	then.
+		no_error_is_returned().and().
+		the_results_are(
+			accountapi.BulkCreateStatusCreated,
+			accountapi.BulkCreateStatusInvalid,
+			accountapi.BulkCreateStatusDuplicate,
+			accountapi.BulkCreateStatusForbidden,
+			accountapi.BulkCreateStatusCreated,
+		).and().
+		only_the_created_accounts_exist()
+}
+
+func TestAcc_Bulk_AtomicCreateOfValidAccounts(t *testing.T) {
+	given, when, then := AccountBulkTest(t)
+
+	given.
+		accounts_to_create(2).and().
+		an_account_without_a_number()
+
+	when.
+		creating_the_accounts_atomically()
+
+	then.
+		no_error_is_returned().and().
+		the_results_are(accountapi.BulkCreateStatusCreated).and().
+		only_the_created_accounts_exist()
+}
+
+func TestAcc_Bulk_AtomicCreateIsRolledBackOnADuplicate(t *testing.T) {
+	given, when, then := AccountBulkTest(t)
+
+	given.
+		accounts_to_create(2).and().
+		an_account_with_the_number_of_the_first()
+
+	when.
+		creating_the_accounts_atomically()
+
+	then.
+		no_error_is_returned().and().
+		the_results_are(
+			accountapi.BulkCreateStatusRolledBack,
+			accountapi.BulkCreateStatusRolledBack,
+			accountapi.BulkCreateStatusDuplicate,
+		).and().
+		only_the_created_accounts_exist()
+}
+
+func TestAcc_Bulk_AtomicCreateIsRolledBackOnAnInvalidAccount(t *testing.T) {
+	given, when, then := AccountBulkTest(t)
+
+	given.
+		accounts_to_create(2).and().
+		an_invalid_account()
+
+	when.
+		creating_the_accounts_atomically()
+
+	then.
+		no_error_is_returned().and().
+		the_results_are(
+			accountapi.BulkCreateStatusRolledBack,
+			accountapi.BulkCreateStatusRolledBack,
+			accountapi.BulkCreateStatusInvalid,
+		).and().
+		only_the_created_accounts_exist()
+}
+
+func TestAcc_Bulk_CreateNoAccounts(t *testing.T) {
+	_, when, then := AccountBulkTest(t)
+
+	when.
+		creating_the_accounts()
+
+	then.
+		the_error_is(accountapi.ErrValidation)
+}
@@ -0,0 +1,451 @@
+package interview_accountapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountnumbers"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/bankidentifiers"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type accountClientStage struct {
+	t               *testing.T
+	client          *accountapi.Client
+	organisationId  string
+	token           string
+	account         accountapi.Account
+	createdAccounts []accountapi.Account
+	fetchedAccount  *accountapi.Account
+	listedAccounts  *accountapi.AccountListData
+	ctx             context.Context
+	collected       []accountapi.Account
+	proxy           *httptest.Server
+	proxyRequests   int32
+	pointInTime     time.Time
+	error           error
+}
+
+func AccountClientTest(t *testing.T) (*accountClientStage, *accountClientStage, *accountClientStage) {
+	stage := &accountClientStage{
+		t:              t,
+		organisationId: uuid.New().String(),
+		ctx:            context.Background(),
+	}
+	return stage, stage, stage
+}
+
+func (s *accountClientStage) and() *accountClientStage {
+	return s
+}
+
+func newTestAccount(organisationId string, accountNumber string, bankID string) accountapi.Account {
+	return accountapi.Account{
+		ID:             uuid.New().String(),
+		OrganisationID: organisationId,
+		Attributes: accountapi.AccountAttributes{
+			Country:               "GB",
+			BaseCurrency:          "GBP",
+			AccountNumber:         accountNumber,
+			BankID:                bankID,
+			BankIDCode:            "GBDSC",
+			Bic:                   "NWBKGB22",
+			BankAccountName:       "Samantha Holder",
+			AccountClassification: accountapi.AccountClassificationPersonal,
+		},
+	}
+}
+
+func (s *accountClientStage) an_account_api_client() *accountClientStage {
+	s.token = newTestToken(uuid.MustParse(s.organisationId), accountPermissions(AuthoriseAllActions...))
+	s.client = newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort), s.token)
+	return s
+}
+
+func (s *accountClientStage) an_existing_account() *accountClientStage {
+	s.creating_an_account_with_number_and_bank_id("41426819", "400300")
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+// an_account_with_the_first_generated_number takes by hand the number the organisation would be
+// given first under the bank id.
+func (s *accountClientStage) an_account_with_the_first_generated_number() *accountClientStage {
+	number, ok := accountnumbers.SchemeOf("GB").Number("400300", int64(settings.AccountNumberFirstSequence))
+	if !assert.True(s.t, ok) {
+		s.t.FailNow()
+	}
+	s.creating_an_account_with_number_and_bank_id(number, "400300")
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *accountClientStage) accounts_for_the_organisation(count int) *accountClientStage {
+	for i := 0; i < count; i++ {
+		// numbered on from the accounts created so far, as account numbers are unique in an organisation
+		accountNumber := fmt.Sprintf("1%07d", len(s.createdAccounts))
+		created, err := s.client.Create(context.Background(), newTestAccount(s.organisationId, accountNumber, "400300"))
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+		s.createdAccounts = append(s.createdAccounts, *created)
+	}
+	return s
+}
+
+func (s *accountClientStage) accounts_for_the_organisation_named(names ...string) *accountClientStage {
+	for i, name := range names {
+		account := newTestAccount(s.organisationId, fmt.Sprintf("2000000%d", i), "400300")
+		account.Attributes.BankAccountName = name
+		created, err := s.client.Create(context.Background(), account)
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+		s.createdAccounts = append(s.createdAccounts, *created)
+	}
+	return s
+}
+
+// a_proxy_failing_the_first_requests_with_status puts a proxy in front of the API that answers the
+// first failures requests itself with the given status, and forwards the rest.
+func (s *accountClientStage) a_proxy_failing_the_first_requests_with_status(failures int, status int) *accountClientStage {
+	target, _ := url.Parse(fmt.Sprintf("http://localhost:%d", ServerPort))
+	forward := httputil.NewSingleHostReverseProxy(target)
+	s.proxy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(atomic.AddInt32(&s.proxyRequests, 1)) <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.Header().Set("X-Request-Id", uuid.New().String())
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error_message":"proxy failure"}`))
+			return
+		}
+		forward.ServeHTTP(w, r)
+	}))
+	s.t.Cleanup(s.proxy.Close)
+	return s
+}
+
+// a_proxy_losing_the_first_responses puts a proxy in front of the API that forwards every request,
+// but answers the first losses of them with 502 Bad Gateway instead of the API's response.
+func (s *accountClientStage) a_proxy_losing_the_first_responses(losses int) *accountClientStage {
+	target, _ := url.Parse(fmt.Sprintf("http://localhost:%d", ServerPort))
+	forward := httputil.NewSingleHostReverseProxy(target)
+	s.proxy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(atomic.AddInt32(&s.proxyRequests, 1)) <= losses {
+			forward.ServeHTTP(httptest.NewRecorder(), r)
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"error_message":"upstream response lost"}`))
+			return
+		}
+		forward.ServeHTTP(w, r)
+	}))
+	s.t.Cleanup(s.proxy.Close)
+	return s
+}
+
+func (s *accountClientStage) the_client_retries_through_the_proxy() *accountClientStage {
+	s.client = newAccountClient(s.t, s.proxy.URL, s.token).WithRetryPolicy(accountapi.NewRetryPolicy(
+		accountapi.MaxTries(3),
+		accountapi.InitialInterval(10*time.Millisecond),
+	))
+	return s
+}
+
+func (s *accountClientStage) a_point_in_time() *accountClientStage {
+	s.pointInTime = time.Now()
+	return s
+}
+
+func (s *accountClientStage) a_cancelled_context() *accountClientStage {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.ctx = ctx
+	return s
+}
+
+func (s *accountClientStage) creating_an_account_with_number_and_bank_id(accountNumber string, bankID string) *accountClientStage {
+	s.account = newTestAccount(s.organisationId, accountNumber, bankID)
+	var created *accountapi.Account
+	created, s.error = s.client.Create(context.Background(), s.account)
+	if created != nil {
+		s.createdAccounts = append(s.createdAccounts, *created)
+	}
+	return s
+}
+
+func (s *accountClientStage) creating_an_account_without_a_number() *accountClientStage {
+	return s.creating_an_account_with_number_and_bank_id("", "400300")
+}
+
+func (s *accountClientStage) creating_the_same_account_again() *accountClientStage {
+	_, s.error = s.client.Create(context.Background(), s.account)
+	return s
+}
+
+func (s *accountClientStage) creating_an_account_without_a_country() *accountClientStage {
+	s.account = newTestAccount(s.organisationId, "41426819", "400300")
+	s.account.Attributes.Country = ""
+	_, s.error = s.client.Create(context.Background(), s.account)
+	return s
+}
+
+func (s *accountClientStage) creating_an_account_with_iban(iban string) *accountClientStage {
+	return s.creating_an_account_with(func(attributes *accountapi.AccountAttributes) {
+		attributes.IBAN = iban
+	})
+}
+
+func (s *accountClientStage) creating_an_account_with_bic(bic string) *accountClientStage {
+	return s.creating_an_account_with(func(attributes *accountapi.AccountAttributes) {
+		attributes.Bic = bic
+	})
+}
+
+func (s *accountClientStage) creating_an_account_with(change func(attributes *accountapi.AccountAttributes)) *accountClientStage {
+	s.account = newTestAccount(s.organisationId, "41426819", "400300")
+	change(&s.account.Attributes)
+	_, s.error = s.client.Create(context.Background(), s.account)
+	return s
+}
+
+func (s *accountClientStage) fetching_the_created_account() *accountClientStage {
+	return s.fetching_an_account_by_id(s.account.ID)
+}
+
+func (s *accountClientStage) fetching_a_non_existing_account() *accountClientStage {
+	return s.fetching_an_account_by_id(uuid.New().String())
+}
+
+func (s *accountClientStage) fetching_an_account_by_id(id string) *accountClientStage {
+	s.fetchedAccount, s.error = s.client.Fetch(context.Background(), id)
+	return s
+}
+
+func (s *accountClientStage) listing_the_accounts_of_the_organisation() *accountClientStage {
+	s.listedAccounts, s.error = s.client.List(context.Background(), accountapi.ListOptions{
+		OrganisationIDs: []string{s.organisationId},
+	})
+	return s
+}
+
+func (s *accountClientStage) listing_the_accounts_of_the_organisation_with(opts accountapi.ListOptions) *accountClientStage {
+	opts.OrganisationIDs = []string{s.organisationId}
+	s.listedAccounts, s.error = s.client.List(context.Background(), opts)
+	return s
+}
+
+func (s *accountClientStage) listing_the_accounts_of_the_organisation_filtered_by(filter map[string][]string) *accountClientStage {
+	return s.listing_the_accounts_of_the_organisation_with(accountapi.ListOptions{Filter: filter})
+}
+
+func (s *accountClientStage) listing_the_accounts_of_the_organisation_created_since_the_point_in_time() *accountClientStage {
+	return s.listing_the_accounts_of_the_organisation_with(accountapi.ListOptions{CreatedFrom: s.pointInTime})
+}
+
+func (s *accountClientStage) listing_the_accounts_of_the_organisation_created_before_the_point_in_time() *accountClientStage {
+	return s.listing_the_accounts_of_the_organisation_with(accountapi.ListOptions{CreatedTo: s.pointInTime})
+}
+
+func (s *accountClientStage) listing_the_accounts_of_the_organisation_sorted_by(sort string) *accountClientStage {
+	return s.listing_the_accounts_of_the_organisation_with(accountapi.ListOptions{Sort: sort})
+}
+
+func (s *accountClientStage) collecting_the_accounts_of_the_organisation_sorted_by_with_page_size(sort string, pageSize int) *accountClientStage {
+	s.collected, s.error = s.client.Iterate(s.ctx, accountapi.ListOptions{
+		PageSize:        pageSize,
+		OrganisationIDs: []string{s.organisationId},
+		Sort:            sort,
+	}).Collect(0)
+	return s
+}
+
+func (s *accountClientStage) collecting_the_accounts_of_the_organisation_with(opts accountapi.ListOptions) *accountClientStage {
+	opts.OrganisationIDs = []string{s.organisationId}
+	s.collected, s.error = s.client.Iterate(s.ctx, opts).Collect(0)
+	return s
+}
+
+// collecting_the_accounts_of_the_organisation_deleting_the_first_one_after_the_first_page deletes
+// the first account once the first page has been read, which shifts the accounts that follow it.
+func (s *accountClientStage) collecting_the_accounts_of_the_organisation_deleting_the_first_one_after_the_first_page(opts accountapi.ListOptions) *accountClientStage {
+	opts.OrganisationIDs = []string{s.organisationId}
+	it := s.client.Iterate(s.ctx, opts)
+	for it.Next() {
+		s.collected = append(s.collected, it.Account())
+		if len(s.collected) == opts.PageSize {
+			err := s.client.Delete(context.Background(), s.createdAccounts[0].ID, s.createdAccounts[0].Version)
+			if !assert.NoError(s.t, err) {
+				s.t.FailNow()
+			}
+		}
+	}
+	s.error = it.Err()
+	return s
+}
+
+func (s *accountClientStage) collecting_the_accounts_of_the_organisation_with_page_size(pageSize int, max int) *accountClientStage {
+	s.collected, s.error = s.client.Iterate(s.ctx, accountapi.ListOptions{
+		PageSize:        pageSize,
+		OrganisationIDs: []string{s.organisationId},
+	}).Collect(max)
+	return s
+}
+
+func (s *accountClientStage) deleting_the_account() *accountClientStage {
+	s.error = s.client.Delete(context.Background(), s.account.ID, 0)
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *accountClientStage) no_error_is_returned() *accountClientStage {
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *accountClientStage) the_fetched_account_matches_the_created_one() *accountClientStage {
+	if !assert.NotNil(s.t, s.fetchedAccount) {
+		return s
+	}
+	assert.Equal(s.t, accountapi.ResourceTypeAccounts, s.fetchedAccount.Type)
+	assert.Equal(s.t, s.account.ID, s.fetchedAccount.ID)
+	assert.Equal(s.t, s.organisationId, s.fetchedAccount.OrganisationID)
+	assert.Equal(s.t, 0, s.fetchedAccount.Version)
+	expected := s.account.Attributes
+	if expected.IBAN == "" {
+		// the IBAN is derived from the other identifiers when it is not given
+		expected.IBAN = s.fetchedAccount.Attributes.IBAN
+	}
+	assert.Equal(s.t, expected, s.fetchedAccount.Attributes)
+	return s
+}
+
+func (s *accountClientStage) the_fetched_account_has_iban(iban string) *accountClientStage {
+	if assert.NotNil(s.t, s.fetchedAccount) {
+		assert.Equal(s.t, iban, s.fetchedAccount.Attributes.IBAN)
+	}
+	return s
+}
+
+// the_created_accounts_have_distinct_valid_numbers checks the numbers of the accounts, generated
+// or not, pass modulus checking, differ from one another and are those of their IBANs.
+func (s *accountClientStage) the_created_accounts_have_distinct_valid_numbers() *accountClientStage {
+	numbers := map[string]bool{}
+	for _, account := range s.createdAccounts {
+		attributes := account.Attributes
+		assert.True(s.t, accountnumbers.SchemeOf(attributes.Country).Valid(attributes.BankID, attributes.AccountNumber), "invalid account number %q", attributes.AccountNumber)
+		assert.False(s.t, numbers[attributes.AccountNumber], "account number %q given twice", attributes.AccountNumber)
+		numbers[attributes.AccountNumber] = true
+
+		iban, err := bankidentifiers.GenerateIBAN(attributes.Country, attributes.BankID, attributes.AccountNumber, attributes.Bic)
+		assert.NoError(s.t, err)
+		assert.Equal(s.t, iban, attributes.IBAN)
+	}
+	return s
+}
+
+func (s *accountClientStage) the_listed_accounts_are_the_created_ones() *accountClientStage {
+	if !assert.NotNil(s.t, s.listedAccounts) {
+		return s
+	}
+	assert.Equal(s.t, s.createdAccounts, s.listedAccounts.Data)
+	if !assert.NotNil(s.t, s.listedAccounts.Links) {
+		return s
+	}
+	assert.NotEmpty(s.t, s.listedAccounts.Links.Self)
+	assert.Empty(s.t, s.listedAccounts.Links.Next)
+	return s
+}
+
+func (s *accountClientStage) the_listed_accounts_are_the_created_ones_at(indexes ...int) *accountClientStage {
+	if !assert.NotNil(s.t, s.listedAccounts) {
+		return s
+	}
+	var expected []accountapi.Account
+	for _, i := range indexes {
+		expected = append(expected, s.createdAccounts[i])
+	}
+	assert.Equal(s.t, expected, s.listedAccounts.Data)
+	return s
+}
+
+func (s *accountClientStage) the_collected_accounts_are_the_created_ones_at(indexes ...int) *accountClientStage {
+	var expected []accountapi.Account
+	for _, i := range indexes {
+		expected = append(expected, s.createdAccounts[i])
+	}
+	assert.Equal(s.t, expected, s.collected)
+	return s
+}
+
+func (s *accountClientStage) the_listed_accounts_have_a_next_but_no_last_link() *accountClientStage {
+	if !assert.NotNil(s.t, s.listedAccounts) || !assert.NotNil(s.t, s.listedAccounts.Links) {
+		return s
+	}
+	assert.NotEmpty(s.t, s.listedAccounts.Links.Next)
+	assert.Empty(s.t, s.listedAccounts.Links.Last)
+	return s
+}
+
+func (s *accountClientStage) the_proxy_received_requests(count int) *accountClientStage {
+	assert.Equal(s.t, int32(count), atomic.LoadInt32(&s.proxyRequests))
+	return s
+}
+
+func (s *accountClientStage) the_collected_accounts_are_the_first_created_ones(count int) *accountClientStage {
+	assert.Equal(s.t, s.createdAccounts[:count], s.collected)
+	return s
+}
+
+func (s *accountClientStage) the_error_is_context_cancelled() *accountClientStage {
+	assert.True(s.t, errors.Is(s.error, context.Canceled), "expected context.Canceled but got %v", s.error)
+	assert.Empty(s.t, s.collected)
+	return s
+}
+
+func (s *accountClientStage) the_error_names_the_field(field string) *accountClientStage {
+	var errorResponse *accountapi.ErrorResponse
+	if assert.True(s.t, errors.As(s.error, &errorResponse), "expected *accountapi.ErrorResponse but got %T", s.error) {
+		assert.Contains(s.t, errorResponse.Message, field)
+	}
+	return s
+}
+
+func (s *accountClientStage) the_error_names_the_first_created_account() *accountClientStage {
+	if assert.NotEmpty(s.t, s.createdAccounts) {
+		s.the_error_names_the_field(s.createdAccounts[0].ID)
+	}
+	return s
+}
+
+func (s *accountClientStage) the_error_is(expected error, statusCode int) *accountClientStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+
+	var errorResponse *accountapi.ErrorResponse
+	if !assert.True(s.t, errors.As(s.error, &errorResponse), "expected *accountapi.ErrorResponse but got %T", s.error) {
+		return s
+	}
+	assert.Equal(s.t, statusCode, errorResponse.StatusCode)
+	assert.NotEmpty(s.t, errorResponse.Message)
+	assert.NotEmpty(s.t, errorResponse.RequestID)
+	return s
+}
@@ -0,0 +1,638 @@
+package interview_accountapi
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_Client_CreateAndFetchAccount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with_number_and_bank_id("41426819", "400300").and().
+		fetching_the_created_account()
+
+	then.
+		no_error_is_returned().and().
+		the_fetched_account_matches_the_created_one()
+}
+
+func TestAcc_Client_FetchNonExistingAccount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		fetching_a_non_existing_account()
+
+	then.
+		the_error_is(accountapi.ErrNotFound, 404)
+}
+
+func TestAcc_Client_FetchInvalidId(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		fetching_an_account_by_id("not-a-uuid")
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400)
+}
+
+func TestAcc_Client_CreateInvalidAccount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_without_a_country()
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400)
+}
+
+func TestAcc_Client_CreateDerivesIBAN(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with_number_and_bank_id("41426819", "400300").and().
+		fetching_the_created_account()
+
+	then.
+		no_error_is_returned().and().
+		the_fetched_account_has_iban("GB16NWBK40030041426819")
+}
+
+func TestAcc_Client_CreateWithInvalidIBANCheckDigits(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with_iban("GB11NWBK40030041426819")
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400).and().
+		the_error_names_the_field("iban")
+}
+
+func TestAcc_Client_CreateWithIBANOfAnotherCountry(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with_iban("DE89370400440532013000")
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400).and().
+		the_error_names_the_field("iban")
+}
+
+func TestAcc_Client_CreateWithBICOfAnotherCountry(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with_bic("DEUTDEFF")
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400).and().
+		the_error_names_the_field("bic")
+}
+
+func TestAcc_Client_CreateWithSortCodeNotFittingTheIBAN(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with_number_and_bank_id("41426819", "4003")
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400).and().
+		the_error_names_the_field("bank_id")
+}
+
+func TestAcc_Client_CreateWithBankIdCodeOfAnotherCountry(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with(func(attributes *accountapi.AccountAttributes) {
+			attributes.BankIDCode = "DEBLZ"
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400).and().
+		the_error_names_the_field("bank_id_code")
+}
+
+func TestAcc_Client_CreateWithCurrencyNotAllowedInTheCountry(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with(func(attributes *accountapi.AccountAttributes) {
+			attributes.BaseCurrency = "USD"
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400).and().
+		the_error_names_the_field("base_currency")
+}
+
+func TestAcc_Client_CreateGermanAccount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_with(func(attributes *accountapi.AccountAttributes) {
+			attributes.Country = "DE"
+			attributes.BaseCurrency = "EUR"
+			attributes.BankID = "37040044"
+			attributes.BankIDCode = "DEBLZ"
+			attributes.AccountNumber = "532013000"
+			attributes.Bic = "COBADEFF"
+		}).and().
+		fetching_the_created_account()
+
+	then.
+		no_error_is_returned().and().
+		the_fetched_account_has_iban("DE89370400440532013000")
+}
+
+func TestAcc_Client_CreateDuplicateAccount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account()
+
+	when.
+		creating_the_same_account_again()
+
+	then.
+		the_error_is(accountapi.ErrConflict, 409)
+}
+
+func TestAcc_Client_CreateAccountWithTheNumberOfAnother(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account()
+
+	when.
+		creating_an_account_with_number_and_bank_id("41426819", "400300")
+
+	then.
+		the_error_is(accountapi.ErrConflict, 409).and().
+		the_error_names_the_first_created_account()
+}
+
+func TestAcc_Client_CreateAccountWithTheNumberOfADeletedOne(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account().and().
+		deleting_the_account()
+
+	when.
+		creating_an_account_with_number_and_bank_id("41426819", "400300")
+
+	then.
+		no_error_is_returned()
+}
+
+func TestAcc_Client_CreateAccountsWithoutNumber(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		creating_an_account_without_a_number().and().
+		creating_an_account_without_a_number()
+
+	then.
+		no_error_is_returned().and().
+		the_created_accounts_have_distinct_valid_numbers()
+}
+
+func TestAcc_Client_CreateAccountWithoutNumberSkipsNumberTakenByHand(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_account_with_the_first_generated_number()
+
+	when.
+		creating_an_account_without_a_number()
+
+	then.
+		no_error_is_returned().and().
+		the_created_accounts_have_distinct_valid_numbers()
+}
+
+func TestAcc_Client_CreateIsReconciledAfterLostResponse(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		a_proxy_losing_the_first_responses(1).and().
+		the_client_retries_through_the_proxy()
+
+	when.
+		creating_an_account_with_number_and_bank_id("41426819", "400300").and().
+		fetching_the_created_account()
+
+	then.
+		no_error_is_returned().and().
+		the_fetched_account_matches_the_created_one().and().
+		the_proxy_received_requests(4)
+}
+
+func TestAcc_Client_ListAccountsOfOrganisation(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_of_the_organisation()
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones()
+}
+
+func TestAcc_Client_ListAccountsFilteredBySortCodeAndAccountNumber(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_of_the_organisation_filtered_by(map[string][]string{
+			"bank_id":        {"400300"},
+			"account_number": {"10000001"},
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(1)
+}
+
+func TestAcc_Client_ListAccountsFilteredByAnyOfSeveralValues(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_of_the_organisation_filtered_by(map[string][]string{
+			"account_number": {"10000000", "10000002"},
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(0, 2)
+}
+
+func TestAcc_Client_ListAccountsFilteredByUnmatchedAttribute(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(2)
+
+	when.
+		listing_the_accounts_of_the_organisation_filtered_by(map[string][]string{
+			"country": {"FR"},
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at()
+}
+
+func TestAcc_Client_ListAccountsCreatedSince(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(2).and().
+		a_point_in_time().and().
+		accounts_for_the_organisation(2)
+
+	when.
+		listing_the_accounts_of_the_organisation_created_since_the_point_in_time()
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(2, 3)
+}
+
+func TestAcc_Client_ListAccountsCreatedBefore(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(2).and().
+		a_point_in_time().and().
+		accounts_for_the_organisation(2)
+
+	when.
+		listing_the_accounts_of_the_organisation_created_before_the_point_in_time()
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(0, 1)
+}
+
+func TestAcc_Client_ListAccountsWithInvalidDateFilter(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		listing_the_accounts_of_the_organisation_filtered_by(map[string][]string{
+			"created_on_from": {"yesterday"},
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400)
+}
+
+func TestAcc_Client_ListAccountsNewestFirst(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_of_the_organisation_sorted_by("-created_on")
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(2, 1, 0)
+}
+
+func TestAcc_Client_ListAccountsAlphabetically(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation_named("Carol Jones", "Alice Smith", "Bob Brown", "Alice Smith")
+
+	when.
+		listing_the_accounts_of_the_organisation_sorted_by("bank_account_name,-created_on")
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(3, 1, 2, 0)
+}
+
+func TestAcc_Client_SortIsKeptAcrossPages(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(5)
+
+	when.
+		collecting_the_accounts_of_the_organisation_sorted_by_with_page_size("-created_on", 2)
+
+	then.
+		no_error_is_returned().and().
+		the_collected_accounts_are_the_created_ones_at(4, 3, 2, 1, 0)
+}
+
+func TestAcc_Client_ListAccountsSortedOnUnknownField(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		listing_the_accounts_of_the_organisation_sorted_by("-record")
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400)
+}
+
+func TestAcc_Client_IterateWithCursor(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(5)
+
+	when.
+		collecting_the_accounts_of_the_organisation_with(accountapi.ListOptions{PageSize: 2, Cursor: true})
+
+	then.
+		no_error_is_returned().and().
+		the_collected_accounts_are_the_created_ones_at(0, 1, 2, 3, 4)
+}
+
+func TestAcc_Client_CursorIsNotShiftedByDeletes(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(5)
+
+	when.
+		collecting_the_accounts_of_the_organisation_deleting_the_first_one_after_the_first_page(accountapi.ListOptions{PageSize: 2, Cursor: true})
+
+	then.
+		no_error_is_returned().and().
+		the_collected_accounts_are_the_created_ones_at(0, 1, 2, 3, 4)
+}
+
+func TestAcc_Client_ListAccountsWithoutCount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_of_the_organisation_with(accountapi.ListOptions{PageSize: 2, SkipCount: true})
+
+	then.
+		no_error_is_returned().and().
+		the_listed_accounts_are_the_created_ones_at(0, 1).and().
+		the_listed_accounts_have_a_next_but_no_last_link()
+}
+
+func TestAcc_Client_IterateWithoutCount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(4)
+
+	when.
+		collecting_the_accounts_of_the_organisation_with(accountapi.ListOptions{PageSize: 2, SkipCount: true})
+
+	then.
+		no_error_is_returned().and().
+		the_collected_accounts_are_the_created_ones_at(0, 1, 2, 3)
+}
+
+func TestAcc_Client_CursorCannotBeSorted(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		listing_the_accounts_of_the_organisation_with(accountapi.ListOptions{Cursor: true, Sort: "-created_on"})
+
+	then.
+		the_error_is(accountapi.ErrValidation, 400)
+}
+
+func TestAcc_Client_DeleteAccount(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account()
+
+	when.
+		deleting_the_account().and().
+		fetching_the_created_account()
+
+	then.
+		the_error_is(accountapi.ErrNotFound, 404)
+}
+
+func TestAcc_Client_IterateAllPages(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(5)
+
+	when.
+		collecting_the_accounts_of_the_organisation_with_page_size(2, 0)
+
+	then.
+		no_error_is_returned().and().
+		the_collected_accounts_are_the_first_created_ones(5)
+}
+
+func TestAcc_Client_IterateStopsAtMax(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(5)
+
+	when.
+		collecting_the_accounts_of_the_organisation_with_page_size(2, 3)
+
+	then.
+		no_error_is_returned().and().
+		the_collected_accounts_are_the_first_created_ones(3)
+}
+
+func TestAcc_Client_IterateStopsOnCancelledContext(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		accounts_for_the_organisation(3).and().
+		a_cancelled_context()
+
+	when.
+		collecting_the_accounts_of_the_organisation_with_page_size(2, 0)
+
+	then.
+		the_error_is_context_cancelled()
+}
+
+func TestAcc_Client_FetchIsRetriedOnServiceUnavailable(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account().and().
+		a_proxy_failing_the_first_requests_with_status(2, 503).and().
+		the_client_retries_through_the_proxy()
+
+	when.
+		fetching_the_created_account()
+
+	then.
+		no_error_is_returned().and().
+		the_fetched_account_matches_the_created_one().and().
+		the_proxy_received_requests(3)
+}
+
+func TestAcc_Client_FetchGivesUpAfterMaxTries(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account().and().
+		a_proxy_failing_the_first_requests_with_status(5, 503).and().
+		the_client_retries_through_the_proxy()
+
+	when.
+		fetching_the_created_account()
+
+	then.
+		the_error_is(accountapi.ErrServer, 503).and().
+		the_proxy_received_requests(3)
+}
+
+func TestAcc_Client_DeleteIsRetriedOnBadGateway(t *testing.T) {
+	given, when, then := AccountClientTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_existing_account().and().
+		a_proxy_failing_the_first_requests_with_status(1, 502).and().
+		the_client_retries_through_the_proxy()
+
+	when.
+		deleting_the_account().and().
+		fetching_the_created_account()
+
+	then.
+		the_error_is(accountapi.ErrNotFound, 404)
+}
@@ -0,0 +1,173 @@
+package interview_accountapi
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type accountExportStage struct {
+	t               *testing.T
+	organisationId  uuid.UUID
+	token           string
+	client          *accountapi.Client
+	createdAccounts []accountapi.Account
+	acceptGzip      bool
+	response        *http.Response
+	body            []byte
+}
+
+func AccountExportTest(t *testing.T) (*accountExportStage, *accountExportStage, *accountExportStage) {
+	organisationId := uuid.New()
+	token := newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))
+	stage := &accountExportStage{
+		t:              t,
+		organisationId: organisationId,
+		token:          token,
+		client:         newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort), token),
+	}
+	return stage, stage, stage
+}
+
+func (s *accountExportStage) and() *accountExportStage {
+	return s
+}
+
+func (s *accountExportStage) accounts_for_the_organisation(count int) *accountExportStage {
+	for i := 0; i < count; i++ {
+		account := newTestAccount(s.organisationId.String(), fmt.Sprintf("5%07d", len(s.createdAccounts)), "400300")
+		account.Attributes.AlternativeBankAccountNames = []string{"Sam Holder", "S Holder"}
+		created, err := s.client.Create(context.Background(), account)
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+		s.createdAccounts = append(s.createdAccounts, *created)
+	}
+	return s
+}
+
+func (s *accountExportStage) an_account_of_another_organisation() *accountExportStage {
+	otherOrganisationId := uuid.New()
+	other := newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort),
+		newTestToken(otherOrganisationId, accountPermissions(AuthoriseAllActions...)))
+	_, err := other.Create(context.Background(), newTestAccount(otherOrganisationId.String(), "59999999", "400300"))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *accountExportStage) the_client_accepts_gzip() *accountExportStage {
+	s.acceptGzip = true
+	return s
+}
+
+func (s *accountExportStage) exporting_as(format string) *accountExportStage {
+	return s.exporting_with(url.Values{"format": {format}})
+}
+
+func (s *accountExportStage) exporting_with(query url.Values) *accountExportStage {
+	request, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("http://localhost:%d/v1/organisation/accounts/export?%s", ServerPort, query.Encode()), nil)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	// an explicit Accept-Encoding keeps the transport from decompressing the response itself
+	request.Header.Set("Accept-Encoding", "identity")
+	if s.acceptGzip {
+		request.Header.Set("Accept-Encoding", "gzip")
+	}
+	s.response, err = authorisedHTTPClient(s.token).Do(request)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	defer s.response.Body.Close()
+
+	var body io.Reader = s.response.Body
+	if s.response.Header.Get("Content-Encoding") == "gzip" {
+		unzipped, err := gzip.NewReader(s.response.Body)
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+		body = unzipped
+	}
+	s.body, err = ioutil.ReadAll(body)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *accountExportStage) the_status_code_is(statusCode int) *accountExportStage {
+	assert.Equal(s.t, statusCode, s.response.StatusCode)
+	return s
+}
+
+func (s *accountExportStage) the_content_type_is(contentType string) *accountExportStage {
+	assert.Equal(s.t, contentType, s.response.Header.Get("Content-Type"))
+	return s
+}
+
+func (s *accountExportStage) the_response_is_gzipped() *accountExportStage {
+	assert.Equal(s.t, "gzip", s.response.Header.Get("Content-Encoding"))
+	return s
+}
+
+// the_csv_rows_are_the_created_accounts_at checks the rows follow the header in the order the
+// accounts were created, with their attributes flattened.
+func (s *accountExportStage) the_csv_rows_are_the_created_accounts_at(indexes ...int) *accountExportStage {
+	rows, err := csv.NewReader(bytes.NewReader(s.body)).ReadAll()
+	if !assert.NoError(s.t, err) || !assert.NotEmpty(s.t, rows) {
+		return s
+	}
+	assert.Equal(s.t, accountexport.Columns(), rows[0])
+	if !assert.Len(s.t, rows[1:], len(indexes)) {
+		return s
+	}
+	for i, index := range indexes {
+		row := map[string]string{}
+		for column, name := range rows[0] {
+			row[name] = rows[i+1][column]
+		}
+		expected := s.createdAccounts[index]
+		assert.Equal(s.t, expected.ID, row["id"])
+		assert.Equal(s.t, expected.OrganisationID, row["organisation_id"])
+		assert.Equal(s.t, expected.Attributes.AccountNumber, row["account_number"])
+		assert.Equal(s.t, expected.Attributes.IBAN, row["iban"])
+		assert.Equal(s.t, "Sam Holder;S Holder", row["alternative_bank_account_names"])
+	}
+	return s
+}
+
+func (s *accountExportStage) the_ndjson_lines_are_the_created_accounts_at(indexes ...int) *accountExportStage {
+	scanner := bufio.NewScanner(bytes.NewReader(s.body))
+	var exported []accountapi.Account
+	for scanner.Scan() {
+		account := accountapi.Account{}
+		if !assert.NoError(s.t, json.Unmarshal(scanner.Bytes(), &account)) {
+			return s
+		}
+		exported = append(exported, account)
+	}
+	if !assert.Len(s.t, exported, len(indexes)) {
+		return s
+	}
+	for i, index := range indexes {
+		assert.Equal(s.t, s.createdAccounts[index], exported[i])
+	}
+	return s
+}
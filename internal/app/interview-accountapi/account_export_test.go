@@ -0,0 +1,118 @@
+package interview_accountapi
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestAcc_Export_AccountsAsCSV(t *testing.T) {
+	given, when, then := AccountExportTest(t)
+
+	given.
+		accounts_for_the_organisation(3)
+
+	when.
+		exporting_as("csv")
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_content_type_is("text/csv; charset=utf-8").and().
+		the_csv_rows_are_the_created_accounts_at(0, 1, 2)
+}
+
+func TestAcc_Export_AccountsAsNDJSON(t *testing.T) {
+	given, when, then := AccountExportTest(t)
+
+	given.
+		accounts_for_the_organisation(2)
+
+	when.
+		exporting_as("ndjson")
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_content_type_is("application/x-ndjson").and().
+		the_ndjson_lines_are_the_created_accounts_at(0, 1)
+}
+
+func TestAcc_Export_IsCSVByDefault(t *testing.T) {
+	given, when, then := AccountExportTest(t)
+
+	given.
+		accounts_for_the_organisation(1)
+
+	when.
+		exporting_with(url.Values{})
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_content_type_is("text/csv; charset=utf-8").and().
+		the_csv_rows_are_the_created_accounts_at(0)
+}
+
+func TestAcc_Export_OnlyTheFilteredAccounts(t *testing.T) {
+	given, when, then := AccountExportTest(t)
+
+	given.
+		accounts_for_the_organisation(3)
+
+	when.
+		exporting_with(url.Values{"format": {"csv"}, "filter[account_number]": {"50000001"}})
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_csv_rows_are_the_created_accounts_at(1)
+}
+
+func TestAcc_Export_OnlyTheAccountsOfTheOrganisationsOfTheUser(t *testing.T) {
+	given, when, then := AccountExportTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		an_account_of_another_organisation()
+
+	when.
+		exporting_as("ndjson")
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_ndjson_lines_are_the_created_accounts_at(0)
+}
+
+func TestAcc_Export_NoAccountsIsTheHeaderAlone(t *testing.T) {
+	_, when, then := AccountExportTest(t)
+
+	when.
+		exporting_as("csv")
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_csv_rows_are_the_created_accounts_at()
+}
+
+func TestAcc_Export_GzippedWhenTheClientAcceptsIt(t *testing.T) {
+	given, when, then := AccountExportTest(t)
+
+	given.
+		accounts_for_the_organisation(2).and().
+		the_client_accepts_gzip()
+
+	when.
+		exporting_as("ndjson")
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_response_is_gzipped().and().
+		the_ndjson_lines_are_the_created_accounts_at(0, 1)
+}
+
+func TestAcc_Export_UnknownFormatIsRejected(t *testing.T) {
+	_, when, then := AccountExportTest(t)
+
+	when.
+		exporting_as("xml")
+
+	then.
+		the_status_code_is(http.StatusBadRequest)
+}
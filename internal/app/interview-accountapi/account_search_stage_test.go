@@ -0,0 +1,121 @@
+package interview_accountapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type accountSearchStage struct {
+	t              *testing.T
+	organisationId uuid.UUID
+	client         *accountapi.Client
+	accounts       []*accountapi.Account
+	matches        []accountapi.AccountMatch
+	error          error
+}
+
+func AccountSearchTest(t *testing.T) (*accountSearchStage, *accountSearchStage, *accountSearchStage) {
+	organisationId := uuid.New()
+	stage := &accountSearchStage{
+		t:              t,
+		organisationId: organisationId,
+		client: newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort),
+			newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))),
+	}
+	return stage, stage, stage
+}
+
+func (s *accountSearchStage) and() *accountSearchStage {
+	return s
+}
+
+func (s *accountSearchStage) an_account_named(name string, alternativeNames ...string) *accountSearchStage {
+	return s.an_account(name, alternativeNames, false)
+}
+
+func (s *accountSearchStage) an_account_opted_out_of_matching_named(name string) *accountSearchStage {
+	return s.an_account(name, nil, true)
+}
+
+func (s *accountSearchStage) an_account(name string, alternativeNames []string, optOut bool) *accountSearchStage {
+	account := newTestAccount(s.organisationId.String(), fmt.Sprintf("4000000%d", len(s.accounts)), "400300")
+	account.Attributes.BankAccountName = name
+	account.Attributes.AlternativeBankAccountNames = alternativeNames
+	account.Attributes.AccountMatchingOptOut = optOut
+	created, err := s.client.Create(context.Background(), account)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.accounts = append(s.accounts, created)
+	return s
+}
+
+func (s *accountSearchStage) an_account_of_another_organisation_named(name string) *accountSearchStage {
+	otherOrganisationId := uuid.New()
+	other := newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort),
+		newTestToken(otherOrganisationId, accountPermissions(AuthoriseAllActions...)))
+	account := newTestAccount(otherOrganisationId.String(), "49999999", "400300")
+	account.Attributes.BankAccountName = name
+	_, err := other.Create(context.Background(), account)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *accountSearchStage) searching_for(name string) *accountSearchStage {
+	s.matches, s.error = s.client.Search(context.Background(), name, accountapi.SearchOptions{})
+	return s
+}
+
+func (s *accountSearchStage) searching_the_first_account_number_for(name string) *accountSearchStage {
+	s.matches, s.error = s.client.Search(context.Background(), name, accountapi.SearchOptions{
+		BankID:        s.accounts[0].Attributes.BankID,
+		AccountNumber: s.accounts[0].Attributes.AccountNumber,
+	})
+	return s
+}
+
+func (s *accountSearchStage) no_error_is_returned() *accountSearchStage {
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+// the_matches_are checks the matched accounts, by their index in the order they were created, and
+// the strength of each match.
+func (s *accountSearchStage) the_matches_are(expected ...interface{}) *accountSearchStage {
+	if !assert.Len(s.t, s.matches, len(expected)/2) {
+		return s
+	}
+	for i := 0; i < len(expected); i += 2 {
+		match := s.matches[i/2]
+		assert.Equal(s.t, s.accounts[expected[i].(int)].ID, match.Account.ID)
+		assert.Equal(s.t, expected[i+1].(accountapi.MatchStrength), match.MatchStrength)
+	}
+	return s
+}
+
+func (s *accountSearchStage) the_first_match_is_on_name(name string) *accountSearchStage {
+	if assert.NotEmpty(s.t, s.matches) {
+		assert.Equal(s.t, name, s.matches[0].MatchedName)
+	}
+	return s
+}
+
+func (s *accountSearchStage) there_are_no_matches() *accountSearchStage {
+	assert.Empty(s.t, s.matches)
+	return s
+}
+
+func (s *accountSearchStage) the_error_is(expected error) *accountSearchStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+	return s
+}
@@ -0,0 +1,106 @@
+package interview_accountapi
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_SearchAccounts_ExactMatch(t *testing.T) {
+	given, when, then := AccountSearchTest(t)
+
+	given.
+		an_account_named("Samantha Holder").and().
+		an_account_named("Barry White")
+
+	when.
+		searching_for("ms samantha holder")
+
+	then.
+		no_error_is_returned().and().
+		the_matches_are(0, accountapi.MatchStrengthExact)
+}
+
+func TestAcc_SearchAccounts_CloseMatchesAfterExactOnes(t *testing.T) {
+	given, when, then := AccountSearchTest(t)
+
+	given.
+		an_account_named("Samanta Holder").and().
+		an_account_named("Samantha Holder").and().
+		an_account_named("S Holder")
+
+	when.
+		searching_for("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_matches_are(1, accountapi.MatchStrengthExact, 0, accountapi.MatchStrengthClose, 2, accountapi.MatchStrengthClose)
+}
+
+func TestAcc_SearchAccounts_MatchOnAlternativeName(t *testing.T) {
+	given, when, then := AccountSearchTest(t)
+
+	given.
+		an_account_named("Samantha Holder", "Sam Holder")
+
+	when.
+		searching_for("Sam Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_matches_are(0, accountapi.MatchStrengthExact).and().
+		the_first_match_is_on_name("Sam Holder")
+}
+
+func TestAcc_SearchAccounts_OptedOutAccountsAreNotMatched(t *testing.T) {
+	given, when, then := AccountSearchTest(t)
+
+	given.
+		an_account_opted_out_of_matching_named("Samantha Holder")
+
+	when.
+		searching_for("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		there_are_no_matches()
+}
+
+func TestAcc_SearchAccounts_OtherOrganisationsAreNotSearched(t *testing.T) {
+	given, when, then := AccountSearchTest(t)
+
+	given.
+		an_account_of_another_organisation_named("Samantha Holder")
+
+	when.
+		searching_for("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		there_are_no_matches()
+}
+
+func TestAcc_SearchAccounts_AccountNumberWithoutMatchingName(t *testing.T) {
+	given, when, then := AccountSearchTest(t)
+
+	given.
+		an_account_named("Samantha Holder").and().
+		an_account_named("Barry White")
+
+	when.
+		searching_the_first_account_number_for("Barry White")
+
+	then.
+		no_error_is_returned().and().
+		the_matches_are(0, accountapi.MatchStrengthNone)
+}
+
+func TestAcc_SearchAccounts_NameIsRequired(t *testing.T) {
+	_, when, then := AccountSearchTest(t)
+
+	when.
+		searching_for("")
+
+	then.
+		the_error_is(accountapi.ErrValidation)
+}
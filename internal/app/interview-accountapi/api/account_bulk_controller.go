@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCreateAccounts creates each of an array of accounts as HandleCreateAccount would, and
+// answers with what became of each one, in the order they were given.
+func HandleCreateAccounts(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debug("Handling bulk create accounts")
+
+	atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "false"))
+	if err != nil {
+		return errors.NewIllegalArgumentError("atomic must be true or false")
+	}
+	var newAccounts []*models.AccountCreation
+	if err := c.BindJSON(&newAccounts); err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	if len(newAccounts) == 0 {
+		return errors.NewIllegalArgumentError("at least one account is required")
+	}
+	if len(newAccounts) > settings.MaxBulkCreateSize {
+		return errors.NewIllegalArgumentError(fmt.Sprintf("at most %d accounts can be created at once, got %d", settings.MaxBulkCreateSize, len(newAccounts)))
+	}
+
+	command := commands.CreateAccountsCommand{
+		DataRecords: make([]*internalmodels.AccountRecord, len(newAccounts)),
+		Outcomes:    make([]commands.CreateAccountOutcome, len(newAccounts)),
+		Atomic:      atomic,
+	}
+	for i, newAccount := range newAccounts {
+		if newAccount == nil {
+			newAccount = &models.AccountCreation{}
+		}
+		dataRecord, err := toNewAccountRecord(newAccount)
+		if err != nil {
+			command.Outcomes[i] = commands.CreateAccountOutcome{Status: commands.AccountInvalid, Reason: err.Error()}
+			continue
+		}
+		command.DataRecords[i] = dataRecord
+	}
+
+	// the permissions of each account are checked by the handler, as they may be of several organisations
+	if err := executors.InMemoryCommandExecutor.Execute(ctx, nil, command); err != nil {
+		return err
+	}
+
+	response := &models.AccountBulkCreationResponse{Data: make([]*models.AccountBulkCreationResult, len(newAccounts))}
+	for i, outcome := range command.Outcomes {
+		result := &models.AccountBulkCreationResult{Status: string(outcome.Status), Reason: outcome.Reason}
+		if newAccounts[i] != nil && newAccounts[i].Data != nil {
+			result.ID = newAccounts[i].Data.ID
+		}
+		response.Data[i] = result
+	}
+	c.JSON(http.StatusOK, response)
+	return nil
+}
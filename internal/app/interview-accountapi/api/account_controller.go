@@ -2,17 +2,24 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/form3tech/go-form3-web/web"
+	"github.com/form3tech/go-security/security"
 
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/bankidentifiers"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/countryrules"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/queries"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/convert"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
@@ -35,13 +42,9 @@ func HandleCreateAccount(ctx *context.Context, c *gin.Context) error {
 	if err := c.BindJSON(newAccount); err != nil {
 		return errors.NewIllegalArgumentError(err.Error())
 	}
-	if err := newAccount.Validate(strfmt.NewFormats()); err != nil {
-		return errors.NewIllegalArgumentError(err.Error())
-	}
-
-	dataRecord, err := convert.ToAccountDataRecord(newAccount)
+	dataRecord, err := toNewAccountRecord(newAccount)
 	if err != nil {
-		return errors.NewIllegalArgumentError(err.Error())
+		return err
 	}
 
 	err = executors.InMemoryCommandExecutor.Execute(ctx, &dataRecord.OrganisationID, commands.CreateAccountCommand{
@@ -74,8 +77,14 @@ func HandleGetAccountById(ctx *context.Context, c *gin.Context) error {
 		return err
 	}
 
+	includeDeleted, err := getIncludeDeleted(ctx, c)
+	if err != nil {
+		return err
+	}
+
 	result := &queries.GetAccountByIdResult{}
-	err = executors.QueryExecutor.Execute(ctx, queries.GetAccountByIdCriteriaBuilder(accountID), &result)
+	criteria := queries.GetAccountByIdCriteriaBuilder(accountID).WithIncludeDeleted(includeDeleted)
+	err = executors.QueryExecutor.Execute(ctx, criteria, &result)
 	if err != nil {
 		return err
 	}
@@ -84,6 +93,162 @@ func HandleGetAccountById(ctx *context.Context, c *gin.Context) error {
 	return nil
 }
 
+// accountUpdate is the body of a PATCH request. Attributes are kept raw so that attributes which
+// are left out can be told apart from attributes being cleared with null.
+type accountUpdate struct {
+	Data *struct {
+		ID         strfmt.UUID                `json:"id"`
+		Type       string                     `json:"type"`
+		Version    *int64                     `json:"version"`
+		Attributes map[string]json.RawMessage `json:"attributes"`
+	} `json:"data"`
+}
+
+func HandleUpdateAccount(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debugf("Handling update account for %+v", c.Params)
+
+	accountId, err := convert.ToUUID(strfmt.UUID(c.Param("id")))
+	if err != nil {
+		return errors.NewIllegalArgumentError(fmt.Sprintf("id is not a valid uuid"))
+	}
+
+	update := &accountUpdate{}
+	if err := c.BindJSON(update); err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	if update.Data == nil {
+		return errors.NewIllegalArgumentError("data is required")
+	}
+	if update.Data.ID != "" && update.Data.ID != convert.FromUUID(accountId) {
+		return errors.NewIllegalArgumentError("id in body does not match id in path")
+	}
+	if update.Data.Type != "" && update.Data.Type != string(models.ResourceTypeAccounts) {
+		return errors.NewIllegalArgumentError(fmt.Sprintf("type %s is not supported", update.Data.Type))
+	}
+	if update.Data.Version == nil {
+		return errors.NewIllegalArgumentError("version is required")
+	}
+
+	result := &queries.GetAccountByIdResult{}
+	err = executors.QueryExecutor.Execute(ctx, queries.GetAccountByIdCriteriaBuilder(accountId), &result)
+	if err != nil {
+		return err
+	}
+	if result.DataRecord.IsLocked {
+		return errors.NewLockedError(fmt.Sprintf("account %s is locked", accountId))
+	}
+	if *result.DataRecord.Version != *update.Data.Version {
+		return errors.NewConflictError(fmt.Sprintf("invalid version %d, current version is %d", *update.Data.Version, *result.DataRecord.Version))
+	}
+
+	attributes, err := convert.MergeAccountAttributes(result.DataRecord.Record, update.Data.Attributes)
+	if err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	if err := attributes.Validate(strfmt.NewFormats()); err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	// accounts stored before the identifiers were checked can still have their other attributes updated
+	if touchesAny(update.Data.Attributes, identifierAttributes) {
+		if err := validateUpdatedIdentifiers(attributes, update.Data.Attributes); err != nil {
+			return err
+		}
+	}
+
+	dataRecord := result.DataRecord
+	dataRecord.Record = convert.ToAccount(attributes)
+	err = executors.InMemoryCommandExecutor.Execute(ctx, &dataRecord.OrganisationID, commands.UpdateAccountCommand{
+		DataRecord: dataRecord,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = executors.QueryExecutor.Execute(ctx, queries.GetAccountByIdCriteriaBuilder(accountId), &result)
+	if err != nil {
+		return err
+	}
+	response := toAccountDetailsResponse(c, result.DataRecord)
+	c.JSON(http.StatusOK, response)
+	return nil
+}
+
+// toNewAccountRecord validates an account to create, against its formats and the rules of its
+// country, and converts it to the record to store.
+func toNewAccountRecord(newAccount *models.AccountCreation) (*internalmodels.AccountRecord, error) {
+	if newAccount.Data == nil {
+		return nil, errors.NewIllegalArgumentError("data is required")
+	}
+	if err := newAccount.Validate(strfmt.NewFormats()); err != nil {
+		return nil, errors.NewIllegalArgumentError(err.Error())
+	}
+	if err := countryrules.Validate(newAccount.Data.Attributes); err != nil {
+		return nil, errors.NewIllegalArgumentError(err.Error())
+	}
+	if err := validateBankIdentifiers(newAccount.Data.Attributes); err != nil {
+		return nil, err
+	}
+	dataRecord, err := convert.ToAccountDataRecord(newAccount)
+	if err != nil {
+		return nil, errors.NewIllegalArgumentError(err.Error())
+	}
+	return dataRecord, nil
+}
+
+// validateBankIdentifiers checks what the swagger patterns cannot: the IBAN check digits and
+// structure, and that the IBAN and BIC are of the country of the account.
+func validateBankIdentifiers(attributes *models.AccountAttributes) error {
+	if attributes == nil || attributes.Country == nil {
+		return nil
+	}
+	if attributes.Iban != "" {
+		if err := bankidentifiers.ValidateIBAN(attributes.Iban, *attributes.Country); err != nil {
+			return errors.NewIllegalArgumentError(err.Error())
+		}
+	}
+	if attributes.Bic != "" {
+		if err := bankidentifiers.ValidateBIC(attributes.Bic, *attributes.Country); err != nil {
+			return errors.NewIllegalArgumentError(err.Error())
+		}
+	}
+	return nil
+}
+
+// identifierAttributes are the attributes the rules of the country of an account apply to.
+var identifierAttributes = []string{"country", "bank_id", "bank_id_code", "account_number", "bic", "iban", "base_currency"}
+
+// ibanSources are the attributes an IBAN is derived from.
+var ibanSources = []string{"country", "bank_id", "account_number", "bic"}
+
+// validateUpdatedIdentifiers checks the identifiers of an updated account as those of a new one
+// are checked. Like on create, the IBAN is derived from the identifiers it is built from unless
+// the update gives one, so that it does not go stale when they change.
+func validateUpdatedIdentifiers(attributes *models.AccountAttributes, patch map[string]json.RawMessage) error {
+	if touchesAny(patch, ibanSources) && !touchesAny(patch, []string{"iban"}) {
+		attributes.Iban = ""
+	}
+	if err := countryrules.Validate(attributes); err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	if attributes.Iban == "" && attributes.Country != nil {
+		iban, err := bankidentifiers.GenerateIBAN(*attributes.Country, attributes.BankID, attributes.AccountNumber, attributes.Bic)
+		if err != nil {
+			return errors.NewIllegalArgumentError(err.Error())
+		}
+		attributes.Iban = iban
+	}
+	return validateBankIdentifiers(attributes)
+}
+
+func touchesAny(patch map[string]json.RawMessage, names []string) bool {
+	for _, name := range names {
+		if _, ok := patch[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func HandleDeleteAccount(ctx *context.Context, c *gin.Context) error {
 	getLogger(ctx, c).Debugf("Handling delete account for %+v", c.Params)
 
@@ -109,6 +274,9 @@ func HandleDeleteAccount(ctx *context.Context, c *gin.Context) error {
 		c.Status(http.StatusNoContent)
 		return nil
 	}
+	if result.DataRecord.IsLocked {
+		return errors.NewLockedError(fmt.Sprintf("account %s is locked", accountId))
+	}
 	if *result.DataRecord.Version != version {
 		return errors.NewNotFoundError("invalid version")
 	}
@@ -123,17 +291,70 @@ func HandleDeleteAccount(ctx *context.Context, c *gin.Context) error {
 	return nil
 }
 
+func HandleLockAccount(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debugf("Handling lock account for %+v", c.Params)
+	return changeAccountLock(ctx, c, true)
+}
+
+func HandleUnlockAccount(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debugf("Handling unlock account for %+v", c.Params)
+	return changeAccountLock(ctx, c, false)
+}
+
+// changeAccountLock locks or unlocks the account at the version given in the query. An account
+// that is already in the requested state is returned as it is, so that repeating a request is safe.
+func changeAccountLock(ctx *context.Context, c *gin.Context, locked bool) error {
+	accountId, err := convert.ToUUID(strfmt.UUID(c.Param("id")))
+	if err != nil {
+		return errors.NewIllegalArgumentError(fmt.Sprintf("id is not a valid uuid"))
+	}
+	version, err := strconv.ParseInt(c.Query("version"), 10, 0)
+	if err != nil {
+		return errors.NewIllegalArgumentError(fmt.Sprintf("invalid version number"))
+	}
+
+	result := &queries.GetAccountByIdResult{}
+	err = executors.QueryExecutor.Execute(ctx, queries.GetAccountByIdCriteriaBuilder(accountId), &result)
+	if err != nil {
+		return err
+	}
+	if result.DataRecord.IsLocked != locked {
+		if *result.DataRecord.Version != version {
+			return errors.NewConflictError(fmt.Sprintf("invalid version %d, current version is %d", version, *result.DataRecord.Version))
+		}
+		var command interface{} = commands.LockAccountCommand{AccountId: accountId, Version: version}
+		if !locked {
+			command = commands.UnlockAccountCommand{AccountId: accountId, Version: version}
+		}
+		if err := executors.InMemoryCommandExecutor.Execute(ctx, &result.DataRecord.OrganisationID, command); err != nil {
+			return err
+		}
+		err = executors.QueryExecutor.Execute(ctx, queries.GetAccountByIdCriteriaBuilder(accountId), &result)
+		if err != nil {
+			return err
+		}
+	}
+	response := toAccountDetailsResponse(c, result.DataRecord)
+	c.JSON(http.StatusOK, response)
+	return nil
+}
+
 func HandleListAccounts(ctx *context.Context, c *gin.Context) error {
 	getLogger(ctx, c).Debugf("Handling list accounts for %+v", c.Params)
 
-	organisationIds, err := convert.ToUUIDs(c.QueryArray("filter[organisation_id]"))
+	builder, sort, err := getListCriteria(ctx, c)
 	if err != nil {
-		return errors.NewIllegalArgumentError(err.Error())
+		return err
 	}
-	criteria := queries.NewListAccountsCriteriaBuilder().
-		WithPageCriteria(web.BuildPageCriteria(c)).
-		WithFilterByOrganisationId(organisationIds).
-		Build()
+	pageCriteria, err := getPageCriteria(c)
+	if err != nil {
+		return err
+	}
+	builder.WithPageCriteria(pageCriteria)
+	if err := withPaging(c, builder, len(sort) > 0); err != nil {
+		return err
+	}
+	criteria := builder.Build()
 
 	result := &queries.ListAccountsResult{}
 	if err := executors.QueryExecutor.Execute(ctx, criteria, &result); err != nil {
@@ -149,7 +370,9 @@ func HandleListAccounts(ctx *context.Context, c *gin.Context) error {
 	response := &models.AccountDetailsListResponse{
 		Data: accounts,
 	}
-	if result.PageResults.TotalRecords != 0 {
+	if !result.Counted {
+		response.Links = convert.FromLinks(buildUncountedListLinks(c, result))
+	} else if result.PageResults.TotalRecords != 0 {
 		links := web.BuildListLinks(c, result.PageResults)
 		response.Links = convert.FromLinks(links)
 	}
@@ -157,6 +380,196 @@ func HandleListAccounts(ctx *context.Context, c *gin.Context) error {
 	return nil
 }
 
+// getListCriteria reads the filters and sort of a list of accounts, which an export shares.
+func getListCriteria(ctx *context.Context, c *gin.Context) (*queries.ListAccountCriteriaBuilder, []queries.SortField, error) {
+	organisationIds, err := convert.ToUUIDs(c.QueryArray("filter[organisation_id]"))
+	if err != nil {
+		return nil, nil, errors.NewIllegalArgumentError(err.Error())
+	}
+	var locked *bool
+	if value := c.Query("filter[locked]"); value != "" {
+		filter, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, errors.NewIllegalArgumentError(fmt.Sprintf("filter[locked] must be true or false"))
+		}
+		locked = &filter
+	}
+	createdFrom, createdTo, err := getTimeRangeFilter(c, "created_on")
+	if err != nil {
+		return nil, nil, err
+	}
+	modifiedFrom, modifiedTo, err := getTimeRangeFilter(c, "modified_on")
+	if err != nil {
+		return nil, nil, err
+	}
+	sort, err := queries.ParseSort(c.Query("sort"))
+	if err != nil {
+		return nil, nil, err
+	}
+	includeDeleted, err := getIncludeDeleted(ctx, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	builder := queries.NewListAccountsCriteriaBuilder().
+		WithFilterByOrganisationId(organisationIds).
+		WithFilterByLocked(locked).
+		WithCreatedOnRange(createdFrom, createdTo).
+		WithModifiedOnRange(modifiedFrom, modifiedTo).
+		WithSort(sort).
+		WithIncludeDeleted(includeDeleted)
+	for _, attribute := range queries.FilterableAttributes {
+		builder.WithFilterByAttribute(attribute, c.QueryArray(fmt.Sprintf("filter[%s]", attribute)))
+	}
+	return builder, sort, nil
+}
+
+// getPageCriteria reads page[number], which is "first", "last" or a page number counting from 0,
+// and page[size], which is at most the configured maximum page size and defaults to it.
+func getPageCriteria(c *gin.Context) (web.PageCriteria, error) {
+	criteria := web.PageCriteria{
+		PageNumber: c.Query("page[number]"),
+		PageSize:   settings.MaxPageSize,
+	}
+	if value := c.Query("page[size]"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 || size > settings.MaxPageSize {
+			return criteria, errors.NewIllegalArgumentError(fmt.Sprintf("page[size] must be a number from 1 to %d", settings.MaxPageSize))
+		}
+		criteria.PageSize = size
+	}
+	switch strings.ToLower(criteria.PageNumber) {
+	case "", "first", "last":
+	default:
+		if number, err := strconv.ParseInt(criteria.PageNumber, 10, 32); err != nil || number < 0 {
+			return criteria, errors.NewIllegalArgumentError(`page[number] must be "first", "last" or a page number from 0`)
+		}
+	}
+	return criteria, nil
+}
+
+// withPaging reads the page[after] and page[before] cursors, which page through the accounts in the
+// order they were created, and page[count], which can turn off counting the accounts.
+func withPaging(c *gin.Context, builder *queries.ListAccountCriteriaBuilder, sorted bool) error {
+	if value := c.Query("page[count]"); value != "" {
+		count, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.NewIllegalArgumentError("page[count] must be true or false")
+		}
+		if !count {
+			builder.WithoutCount()
+		}
+	}
+
+	after, hasAfter := c.GetQuery("page[after]")
+	before, hasBefore := c.GetQuery("page[before]")
+	if !hasAfter && !hasBefore {
+		return nil
+	}
+	if hasAfter && hasBefore {
+		return errors.NewIllegalArgumentError("page[after] and page[before] cannot be combined")
+	}
+	if c.Query("page[number]") != "" || sorted {
+		return errors.NewIllegalArgumentError("page[after] and page[before] cannot be combined with page[number] or sort")
+	}
+
+	// cursors only need the accounts either side of the page, never the count
+	builder.WithoutCount()
+	if hasAfter {
+		paginationId, err := queries.DecodeCursor(after)
+		if err != nil {
+			return err
+		}
+		builder.WithPageAfter(paginationId)
+		return nil
+	}
+	paginationId, err := queries.DecodeCursor(before)
+	if err != nil {
+		return err
+	}
+	builder.WithPageBefore(paginationId)
+	return nil
+}
+
+// buildUncountedListLinks builds the links of a page read without counting the accounts, either by
+// page number, in which case there is no last link, or with a cursor.
+func buildUncountedListLinks(c *gin.Context, result *queries.ListAccountsResult) web.Links {
+	path := web.BuildItemLinks(c, "").Self
+	link := func(name string, value string) string {
+		query := c.Request.URL.Query()
+		query.Del("page[number]")
+		query.Del("page[after]")
+		query.Del("page[before]")
+		query.Set(name, value)
+		return path + "?" + query.Encode()
+	}
+
+	links := web.Links{Self: path}
+	if len(c.Request.URL.Query()) > 0 {
+		links.Self = path + "?" + c.Request.URL.Query().Encode()
+	}
+
+	_, hasAfter := c.GetQuery("page[after]")
+	_, hasBefore := c.GetQuery("page[before]")
+	if hasAfter || hasBefore {
+		links.First = link("page[after]", "")
+		links.Last = link("page[before]", "")
+		if result.NextCursor != "" {
+			links.Next = link("page[after]", result.NextCursor)
+		}
+		if result.PrevCursor != "" {
+			links.Prev = link("page[before]", result.PrevCursor)
+		}
+		return links
+	}
+
+	links.First = link("page[number]", "first")
+	if result.HasNext {
+		links.Next = link("page[number]", strconv.Itoa(result.PageResults.CurrentPage+1))
+	}
+	if result.PageResults.CurrentPage > 0 {
+		links.Prev = link("page[number]", strconv.Itoa(result.PageResults.CurrentPage-1))
+	}
+	return links
+}
+
+// getTimeRangeFilter reads the filter[<field>_from] and filter[<field>_to] query parameters, given
+// as RFC 3339 date-times.
+func getTimeRangeFilter(c *gin.Context, field string) (*time.Time, *time.Time, error) {
+	var bounds [2]*time.Time
+	for i, suffix := range []string{"_from", "_to"} {
+		name := fmt.Sprintf("filter[%s%s]", field, suffix)
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		bound, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, nil, errors.NewIllegalArgumentError(fmt.Sprintf("%s must be an RFC 3339 date-time", name))
+		}
+		bounds[i] = &bound
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// getIncludeDeleted reads the include_deleted query parameter. Only administrators, who are
+// granted READ on the accounts_admin record type, may ask for deleted accounts.
+func getIncludeDeleted(ctx *context.Context, c *gin.Context) (bool, error) {
+	value := c.Query("include_deleted")
+	if value == "" {
+		return false, nil
+	}
+	includeDeleted, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.NewIllegalArgumentError(fmt.Sprintf("include_deleted must be true or false"))
+	}
+	if includeDeleted {
+		if err := security.CheckPermissionForResourceWithoutOrganisation(ctx, settings.AccountsAdminRecordType, security.READ); err != nil {
+			return false, err
+		}
+	}
+	return includeDeleted, nil
+}
+
 func toAccountDetailsResponse(c *gin.Context, data *internalmodels.AccountRecord) (response *models.AccountDetailsResponse) {
 	account := convert.FromAccountDataRecord(data)
 	links := web.BuildItemLinks(c, data.ID.String())
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/queries"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/convert"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleExportAccounts streams every account the filters of a list select, as CSV or NDJSON,
+// gzipped when the client accepts it. The response is only started with the first account, so
+// that a request that fails before then is answered with an error as usual. A failure after that
+// is logged and the connection closed without ending the response, so that the client cannot
+// take the export for complete, as is one the client takes longer than ExportWriteTimeoutSeconds
+// to read any part of.
+func HandleExportAccounts(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debugf("Handling export accounts for %+v", c.Request.URL.Query())
+
+	format := c.DefaultQuery("format", accountexport.CSV)
+	if _, ok := accountexport.ContentTypes[format]; !ok {
+		return errors.NewIllegalArgumentError(fmt.Sprintf("format must be %s or %s", accountexport.CSV, accountexport.NDJSON))
+	}
+	builder, _, err := getListCriteria(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	export := &accountExport{c: c, format: format}
+	criteria := queries.ExportAccountsCriteria{
+		List: builder.Build(),
+		Write: func(record *internalmodels.AccountRecord) error {
+			if err := export.start(); err != nil {
+				return err
+			}
+			if err := export.extendWriteDeadline(); err != nil {
+				return err
+			}
+			return export.writer.Write(convert.FromAccountDataRecord(record))
+		},
+	}
+	result := &queries.ExportAccountsResult{}
+	err = executors.QueryExecutor.Execute(ctx, criteria, &result)
+	if err := export.end(ctx, err); err != nil {
+		return err
+	}
+	getLogger(ctx, c).Debugf("Exported %d accounts", result.Exported)
+	return nil
+}
+
+// accountExport writes the response of an export, which it starts on the first call to start.
+type accountExport struct {
+	c       *gin.Context
+	format  string
+	started bool
+	gzip    *gzip.Writer
+	writer  accountexport.Writer
+}
+
+func (e *accountExport) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	header := e.c.Writer.Header()
+	header.Set("Content-Type", accountexport.ContentTypes[e.format])
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="accounts.%s"`, e.format))
+	header.Add("Vary", "Accept-Encoding")
+	var out io.Writer = e.c.Writer
+	if acceptsGzip(e.c.Request) {
+		header.Set("Content-Encoding", "gzip")
+		e.gzip = gzip.NewWriter(e.c.Writer)
+		out = e.gzip
+	}
+	e.c.Status(http.StatusOK)
+
+	var err error
+	e.writer, err = accountexport.NewWriter(e.format, out)
+	return err
+}
+
+// extendWriteDeadline gives the client ExportWriteTimeoutSeconds to read what is written next. The
+// response has no deadline when the server does not hand its controller to the request.
+func (e *accountExport) extendWriteDeadline() error {
+	controller, ok := e.c.Request.Context().Value(responseControllerKey{}).(*http.ResponseController)
+	if !ok {
+		return nil
+	}
+	return controller.SetWriteDeadline(time.Now().Add(time.Duration(settings.ExportWriteTimeoutSeconds) * time.Second))
+}
+
+// clearWriteDeadline leaves the connection without a deadline for the requests that follow on it.
+func (e *accountExport) clearWriteDeadline() {
+	if controller, ok := e.c.Request.Context().Value(responseControllerKey{}).(*http.ResponseController); ok {
+		_ = controller.SetWriteDeadline(time.Time{})
+	}
+}
+
+func (e *accountExport) finish() error {
+	if err := e.extendWriteDeadline(); err != nil {
+		return err
+	}
+	defer e.clearWriteDeadline()
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+	if e.gzip != nil {
+		return e.gzip.Close()
+	}
+	return nil
+}
+
+// end completes the response once the accounts are written, or the export failed with err. It only
+// returns err when the response is not started yet and can still report it.
+func (e *accountExport) end(ctx *context.Context, err error) error {
+	if err != nil && !e.started {
+		return err
+	}
+	if err == nil {
+		err = e.start()
+	}
+	if err == nil {
+		err = e.finish()
+	}
+	if err != nil {
+		getLogger(ctx, e.c).Errorf("account export cut short: %v", err)
+		e.abort(ctx)
+	}
+	return nil
+}
+
+// abort closes the connection without ending the response. Panicking with http.ErrAbortHandler
+// would not do, as the recovery of gin takes it for any other panic once the response is started.
+func (e *accountExport) abort(ctx *context.Context) {
+	conn, _, err := e.c.Writer.Hijack()
+	if err != nil {
+		getLogger(ctx, e.c).Errorf("cannot close the connection of the export: %v", err)
+		return
+	}
+	_ = conn.Close()
+}
+
+type responseControllerKey struct{}
+
+// withResponseController hands the request the controller of its response, which the writer of gin
+// gives no access to, so that a handler can set deadlines on it.
+func withResponseController(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), responseControllerKey{}, http.NewResponseController(w))
+		handler(w, req.WithContext(ctx))
+	}
+}
+
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountexport"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/convert"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// exportServer serves an export that writes an account, and is then ended with err.
+func exportServer(t *testing.T, err error) *httptest.Server {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/export", func(c *gin.Context) {
+		ctx := context.Background()
+		export := &accountExport{c: c, format: accountexport.CSV}
+		if !assert.NoError(t, export.start()) {
+			return
+		}
+		version := int64(0)
+		record := &internalmodels.AccountRecord{ID: uuid.New(), OrganisationID: uuid.New(), Version: &version}
+		assert.NoError(t, export.writer.Write(convert.FromAccountDataRecord(record)))
+		assert.NoError(t, export.writer.Flush())
+		c.Writer.Flush()
+
+		assert.NoError(t, export.end(&ctx, err))
+	})
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// getExport requests the export uncompressed, where nothing but the end of the response tells a
+// complete export from one cut short.
+func getExport(server *httptest.Server) (*http.Response, error) {
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	return client.Get(server.URL + "/export")
+}
+
+func TestAccountExport_EndsTheResponse(t *testing.T) {
+	resp, err := getExport(exportServer(t, nil))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, body)
+}
+
+func TestAccountExport_ClosesTheConnectionOfAnExportCutShort(t *testing.T) {
+	resp, err := getExport(exportServer(t, errors.New("database connection lost")))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+
+	_, err = ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestAccountExport_CutsShortAnExportTheClientStopsReading(t *testing.T) {
+	defer func(timeout int) { settings.ExportWriteTimeoutSeconds = timeout }(settings.ExportWriteTimeoutSeconds)
+	settings.ExportWriteTimeoutSeconds = 1
+	writeErr := make(chan error, 1)
+	router := gin.New()
+	router.GET("/export", func(c *gin.Context) {
+		export := &accountExport{c: c, format: accountexport.CSV}
+		version := int64(0)
+		record := &internalmodels.AccountRecord{ID: uuid.New(), OrganisationID: uuid.New(), Version: &version,
+			Record: internalmodels.Account{BankAccountName: strings.Repeat("Samantha Holder ", 64)}}
+		for {
+			err := export.start()
+			if err == nil {
+				err = export.extendWriteDeadline()
+			}
+			if err == nil {
+				err = export.writer.Write(convert.FromAccountDataRecord(record))
+			}
+			if err != nil {
+				writeErr <- err
+				return
+			}
+		}
+	})
+	server := httptest.NewServer(withResponseController(router.ServeHTTP))
+	t.Cleanup(server.Close)
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer conn.Close()
+	_, err = io.WriteString(conn, "GET /export HTTP/1.1\r\nHost: localhost\r\n\r\n")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	select {
+	case err := <-writeErr:
+		assert.Error(t, err)
+	case <-time.After(30 * time.Second):
+		t.Fatal("the export kept waiting on a client that stopped reading")
+	}
+}
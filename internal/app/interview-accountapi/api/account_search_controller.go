@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/queries"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/convert"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+func HandleSearchAccounts(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debug("Handling search accounts")
+
+	name := c.Query("name")
+	if name == "" {
+		return errors.NewIllegalArgumentError("name is required")
+	}
+	organisationIds, err := convert.ToUUIDs(c.QueryArray("filter[organisation_id]"))
+	if err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			return errors.NewIllegalArgumentError(fmt.Sprintf("limit must be a number from 1 to %d", maxSearchLimit))
+		}
+	}
+
+	criteria := queries.SearchAccountsCriteria{
+		Name:                    name,
+		OrganisationIds:         organisationIds,
+		BankId:                  c.Query("filter[bank_id]"),
+		AccountNumber:           c.Query("filter[account_number]"),
+		SecondaryIdentification: c.Query("filter[secondary_identification]"),
+		Limit:                   limit,
+	}
+	result := &queries.SearchAccountsResult{}
+	if err := executors.QueryExecutor.Execute(ctx, criteria, &result); err != nil {
+		return err
+	}
+
+	response := &models.AccountSearchResponse{Data: []*models.AccountMatch{}}
+	for _, match := range result.Matches {
+		response.Data = append(response.Data, &models.AccountMatch{
+			Account:       convert.FromAccountDataRecord(match.DataRecord),
+			MatchStrength: string(match.Match.Strength),
+			MatchedName:   match.Match.Name,
+			Score:         match.Match.Score,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package accountexport writes accounts out in bulk: as CSV, one row of flattened attributes per
+// account, or as NDJSON, one account per line as the API returns it.
+package accountexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+)
+
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+)
+
+// ContentTypes are the media types of the formats.
+var ContentTypes = map[string]string{
+	CSV:    "text/csv; charset=utf-8",
+	NDJSON: "application/x-ndjson",
+}
+
+// NameSeparator joins the alternative bank account names of an account into one CSV column.
+const NameSeparator = ";"
+
+type column struct {
+	name  string
+	value func(account *models.Account) string
+}
+
+// columns are those of the CSV export, named after the fields of the API. Attributes that are
+// not set are left empty. The accounts they are given always have attributes.
+var columns = []column{
+	{"id", func(a *models.Account) string { return a.ID.String() }},
+	{"organisation_id", func(a *models.Account) string { return a.OrganisationID.String() }},
+	{"version", func(a *models.Account) string { return formatInt(a.Version) }},
+	{"created_on", func(a *models.Account) string { return a.CreatedOn.String() }},
+	{"modified_on", func(a *models.Account) string { return a.ModifiedOn.String() }},
+	{"locked", func(a *models.Account) string { return strconv.FormatBool(a.Locked) }},
+	{"deleted", func(a *models.Account) string { return strconv.FormatBool(a.Deleted) }},
+	{"country", func(a *models.Account) string { return formatString(a.Attributes.Country) }},
+	{"base_currency", func(a *models.Account) string { return a.Attributes.BaseCurrency }},
+	{"bank_id", func(a *models.Account) string { return a.Attributes.BankID }},
+	{"bank_id_code", func(a *models.Account) string { return a.Attributes.BankIDCode }},
+	{"account_number", func(a *models.Account) string { return a.Attributes.AccountNumber }},
+	{"bic", func(a *models.Account) string { return a.Attributes.Bic }},
+	{"iban", func(a *models.Account) string { return a.Attributes.Iban }},
+	{"customer_id", func(a *models.Account) string { return a.Attributes.CustomerID }},
+	{"title", func(a *models.Account) string { return a.Attributes.Title }},
+	{"first_name", func(a *models.Account) string { return a.Attributes.FirstName }},
+	{"bank_account_name", func(a *models.Account) string { return a.Attributes.BankAccountName }},
+	{"alternative_bank_account_names", func(a *models.Account) string {
+		return strings.Join(a.Attributes.AlternativeBankAccountNames, NameSeparator)
+	}},
+	{"account_classification", func(a *models.Account) string { return formatString(a.Attributes.AccountClassification) }},
+	{"joint_account", func(a *models.Account) string { return formatBool(a.Attributes.JointAccount) }},
+	{"account_matching_opt_out", func(a *models.Account) string { return formatBool(a.Attributes.AccountMatchingOptOut) }},
+	{"secondary_identification", func(a *models.Account) string { return a.Attributes.SecondaryIdentification }},
+}
+
+// Columns returns the names of the columns of the CSV export, in order.
+func Columns() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Writer writes accounts out one at a time. Flush must be called once all of them are written.
+type Writer interface {
+	Write(account *models.Account) error
+	Flush() error
+}
+
+// NewWriter returns a writer of the format, which is CSV or NDJSON.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case NDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	}
+	return nil, fmt.Errorf("format must be %s or %s, got %q", CSV, NDJSON, format)
+}
+
+// csvWriter writes the header row ahead of the first account, or on Flush when there is none.
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) Write(account *models.Account) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	if account.Attributes == nil {
+		withAttributes := *account
+		withAttributes.Attributes = &models.AccountAttributes{}
+		account = &withAttributes
+	}
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		row[i] = column.value(account)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(Columns())
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(account *models.Account) error {
+	return n.encoder.Encode(account)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
+
+func formatString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+func formatInt(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(*value, 10)
+}
@@ -0,0 +1,98 @@
+package accountexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAccount() *models.Account {
+	country := "GB"
+	classification := "Personal"
+	joint := true
+	var version int64 = 2
+	return &models.Account{
+		ID:             strfmt.UUID("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		OrganisationID: strfmt.UUID("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+		Version:        &version,
+		Locked:         true,
+		Attributes: &models.AccountAttributes{
+			Country:                     &country,
+			BankID:                      "400300",
+			AccountNumber:               "41426819",
+			BankAccountName:             "Samantha Holder, Jo Holder",
+			AlternativeBankAccountNames: []string{"Sam Holder", "Jo Holder"},
+			AccountClassification:       &classification,
+			JointAccount:                &joint,
+		},
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var out bytes.Buffer
+	w, err := NewWriter(CSV, &out)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, w.Write(testAccount()))
+	assert.NoError(t, w.Write(&models.Account{ID: strfmt.UUID("a7d2e5c4-2f1e-4b9a-9d0c-1b2f3a4c5d6e")}))
+	assert.NoError(t, w.Flush())
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, rows, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, Columns(), rows[0])
+	row := map[string]string{}
+	for i, name := range rows[0] {
+		row[name] = rows[1][i]
+	}
+	assert.Equal(t, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", row["id"])
+	assert.Equal(t, "2", row["version"])
+	assert.Equal(t, "true", row["locked"])
+	assert.Equal(t, "GB", row["country"])
+	assert.Equal(t, "41426819", row["account_number"])
+	assert.Equal(t, "Samantha Holder, Jo Holder", row["bank_account_name"])
+	assert.Equal(t, "Sam Holder;Jo Holder", row["alternative_bank_account_names"])
+	assert.Equal(t, "Personal", row["account_classification"])
+	assert.Equal(t, "true", row["joint_account"])
+	assert.Equal(t, "", row["account_matching_opt_out"])
+	assert.Equal(t, "", rows[2][len(rows[2])-1])
+}
+
+func TestCSVWithoutAccountsHasItsHeader(t *testing.T) {
+	var out bytes.Buffer
+	w, _ := NewWriter(CSV, &out)
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, strings.Join(Columns(), ",")+"\n", out.String())
+}
+
+func TestNDJSON(t *testing.T) {
+	var out bytes.Buffer
+	w, err := NewWriter(NDJSON, &out)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, w.Write(testAccount()))
+	assert.NoError(t, w.Write(testAccount()))
+	assert.NoError(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if !assert.Len(t, lines, 2) {
+		t.FailNow()
+	}
+	account := &models.Account{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), account))
+	assert.Equal(t, testAccount(), account)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xml", &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Package accountnumbers turns the sequence numbers allocated to a bank id into account numbers of
+// the length of the country of the account, with the check digit the country expects, if any.
+package accountnumbers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckDigit is the way the last digit of an account number is computed from the others.
+type CheckDigit int
+
+const (
+	// NoCheckDigit is for the countries that keep their check digits outside of the account
+	// number, in the BBAN of the IBAN, or have none.
+	NoCheckDigit CheckDigit = iota
+	// Luhn is the modulus 10 check of alternately doubled digits, e.g. method 00 of the German banks.
+	Luhn
+	// Modulus11 makes the weighted sum of the digits a multiple of 11, as UK modulus checking and
+	// the Dutch elfproef do.
+	Modulus11
+)
+
+// Scheme is the format of the account numbers of a country.
+type Scheme struct {
+	// Length is the number of digits of an account number, its check digit included.
+	Length int
+	Check  CheckDigit
+	// Weights are those of the Modulus11 check, from the left, over the bank id followed by the
+	// account number when WeighBankId is set and over the account number alone otherwise.
+	Weights     []int
+	WeighBankId bool
+}
+
+// schemes are the formats of account numbers generated for each country, in line with the account
+// number rules of the country. Those of other countries are 8 digits with a Luhn check digit.
+var schemes = map[string]Scheme{
+	"AU": {Length: 8, Check: NoCheckDigit},
+	"BE": {Length: 7, Check: NoCheckDigit},
+	"CA": {Length: 9, Check: NoCheckDigit},
+	"CH": {Length: 12, Check: NoCheckDigit},
+	"DE": {Length: 10, Check: Luhn},
+	"ES": {Length: 10, Check: NoCheckDigit},
+	"FR": {Length: 11, Check: NoCheckDigit},
+	"GB": {Length: 8, Check: Modulus11, Weights: []int{7, 6, 5, 4, 3, 2, 8, 7, 6, 5, 4, 3, 2, 1}, WeighBankId: true},
+	"GR": {Length: 16, Check: NoCheckDigit},
+	"HK": {Length: 9, Check: NoCheckDigit},
+	"IT": {Length: 12, Check: NoCheckDigit},
+	"LU": {Length: 13, Check: NoCheckDigit},
+	"NL": {Length: 10, Check: Modulus11, Weights: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+	"PL": {Length: 16, Check: NoCheckDigit},
+	"PT": {Length: 11, Check: NoCheckDigit},
+	"US": {Length: 10, Check: Luhn},
+}
+
+var defaultScheme = Scheme{Length: 8, Check: Luhn}
+
+// SchemeOf returns the format of the account numbers of the country.
+func SchemeOf(country string) Scheme {
+	if scheme, ok := schemes[country]; ok {
+		return scheme
+	}
+	return defaultScheme
+}
+
+// MaxSequence is the highest sequence number that fits in an account number.
+func (s Scheme) MaxSequence() int64 {
+	max := int64(1)
+	for i := 0; i < s.sequenceLength(); i++ {
+		max *= 10
+	}
+	return max - 1
+}
+
+// Number returns the account number of the sequence number, padded with zeros and followed by its
+// check digit. It returns false when the sequence number does not fit or has no valid check
+// digit, as happens to one in eleven under Modulus11; the next one should be taken instead.
+func (s Scheme) Number(bankId string, sequence int64) (string, bool) {
+	if sequence < 0 || sequence > s.MaxSequence() {
+		return "", false
+	}
+	number := fmt.Sprintf("%0*d", s.sequenceLength(), sequence)
+	if s.Check == NoCheckDigit {
+		return number, true
+	}
+	digit, ok := s.checkDigit(bankId, number)
+	if !ok {
+		return "", false
+	}
+	return number + digit, true
+}
+
+// Valid tells whether the account number has the length and check digit of the scheme.
+func (s Scheme) Valid(bankId string, number string) bool {
+	if len(number) != s.Length || strings.Trim(number, "0123456789") != "" {
+		return false
+	}
+	if s.Check == NoCheckDigit {
+		return true
+	}
+	digit, ok := s.checkDigit(bankId, number[:s.Length-1])
+	return ok && digit == number[s.Length-1:]
+}
+
+func (s Scheme) sequenceLength() int {
+	if s.Check == NoCheckDigit {
+		return s.Length
+	}
+	return s.Length - 1
+}
+
+func (s Scheme) checkDigit(bankId string, number string) (string, bool) {
+	switch s.Check {
+	case Luhn:
+		return luhnDigit(number), true
+	case Modulus11:
+		payload := number
+		if s.WeighBankId {
+			payload = bankId + number
+		}
+		return modulus11Digit(payload, s.Weights)
+	}
+	return "", false
+}
+
+// luhnDigit doubles every other digit, starting from the rightmost, and sums the digits of the
+// results with the others, which the check digit brings to a multiple of 10.
+func luhnDigit(number string) string {
+	sum := 0
+	for i := len(number) - 1; i >= 0; i -= 2 {
+		doubled := int(number[i]-'0') * 2
+		sum += doubled/10 + doubled%10
+		if i > 0 {
+			sum += int(number[i-1] - '0')
+		}
+	}
+	return fmt.Sprint((10 - sum%10) % 10)
+}
+
+// modulus11Digit is the digit that, weighted by the last weight, brings the weighted sum of the
+// payload to a multiple of 11. The last weight is 1, so there is none when the sum is 1 short of
+// one, or the payload is not as long as the weights.
+func modulus11Digit(payload string, weights []int) (string, bool) {
+	if len(payload) != len(weights)-1 || strings.Trim(payload, "0123456789") != "" {
+		return "", false
+	}
+	sum := 0
+	for i := range payload {
+		sum += int(payload[i]-'0') * weights[i]
+	}
+	digit := (11 - sum%11) % 11
+	if digit == 10 {
+		return "", false
+	}
+	return fmt.Sprint(digit), true
+}
@@ -0,0 +1,73 @@
+package accountnumbers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		country  string
+		bankId   string
+		sequence int64
+		number   string
+	}{
+		{country: "GB", bankId: "400300", sequence: 1, number: "00000012"},
+		{country: "GB", bankId: "400300", sequence: 2, number: "00000020"},
+		{country: "GB", bankId: "400300", sequence: 8, number: ""},
+		{country: "GB", bankId: "400300", sequence: 10000000, number: ""},
+		{country: "NL", sequence: 41716430, number: "0417164300"},
+		{country: "DE", bankId: "37040044", sequence: 799273987, number: "7992739875"},
+		{country: "ES", bankId: "21000418", sequence: 200051332, number: "0200051332"},
+		{country: "NO", sequence: 42, number: "00000422"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+"/"+tt.number, func(t *testing.T) {
+			scheme := SchemeOf(tt.country)
+			number, ok := scheme.Number(tt.bankId, tt.sequence)
+			assert.Equal(t, tt.number, number)
+			assert.Equal(t, tt.number != "", ok)
+			if ok {
+				assert.True(t, scheme.Valid(tt.bankId, number))
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		country string
+		bankId  string
+		number  string
+		valid   bool
+	}{
+		{country: "GB", bankId: "400300", number: "00000012", valid: true},
+		{country: "GB", bankId: "400300", number: "00000013"},
+		{country: "GB", bankId: "400301", number: "00000012"},
+		{country: "GB", bankId: "400300", number: "0000012"},
+		{country: "NL", number: "0417164300", valid: true},
+		{country: "NL", number: "0417164301"},
+		{country: "DE", number: "7992739875", valid: true},
+		{country: "DE", number: "799273987X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+"/"+tt.number, func(t *testing.T) {
+			assert.Equal(t, tt.valid, SchemeOf(tt.country).Valid(tt.bankId, tt.number))
+		})
+	}
+}
+
+func TestSchemesFitTheirWeights(t *testing.T) {
+	for country, scheme := range schemes {
+		if scheme.Check != Modulus11 {
+			continue
+		}
+		length := scheme.Length
+		if scheme.WeighBankId {
+			length += 6
+		}
+		assert.Len(t, scheme.Weights, length, country)
+		assert.Equal(t, 1, scheme.Weights[len(scheme.Weights)-1], country)
+	}
+}
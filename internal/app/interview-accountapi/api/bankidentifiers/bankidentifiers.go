@@ -0,0 +1,233 @@
+// Package bankidentifiers checks IBANs and BICs beyond their format: IBAN check digits and the
+// structure of the BBAN of each country, and whether a BIC belongs to the country of the account.
+// It also builds the IBAN of an account from its local bank identifier and account number.
+package bankidentifiers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FieldError names the attribute that is invalid, so that the caller can tell which one to fix.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s in body %s", e.Field, e.Reason)
+}
+
+type ibanFormat struct {
+	length int
+	bban   *regexp.Regexp
+}
+
+// ibanFormats are the IBAN length and BBAN structure of the countries checked in full. The IBANs
+// of other countries only have their check digits and overall length checked.
+var ibanFormats = map[string]ibanFormat{
+	"AT": {20, regexp.MustCompile(`^[0-9]{16}$`)},
+	"BE": {16, regexp.MustCompile(`^[0-9]{12}$`)},
+	"CH": {21, regexp.MustCompile(`^[0-9]{5}[A-Z0-9]{12}$`)},
+	"DE": {22, regexp.MustCompile(`^[0-9]{18}$`)},
+	"ES": {24, regexp.MustCompile(`^[0-9]{20}$`)},
+	"FR": {27, regexp.MustCompile(`^[0-9]{10}[A-Z0-9]{11}[0-9]{2}$`)},
+	"GB": {22, regexp.MustCompile(`^[A-Z]{4}[0-9]{14}$`)},
+	"IE": {22, regexp.MustCompile(`^[A-Z]{4}[0-9]{14}$`)},
+	"IT": {27, regexp.MustCompile(`^[A-Z][0-9]{10}[A-Z0-9]{12}$`)},
+	"LU": {20, regexp.MustCompile(`^[0-9]{3}[A-Z0-9]{13}$`)},
+	"NL": {18, regexp.MustCompile(`^[A-Z]{4}[0-9]{10}$`)},
+	"PT": {25, regexp.MustCompile(`^[0-9]{21}$`)},
+}
+
+const (
+	minIbanLength = 15
+	maxIbanLength = 34
+)
+
+// bankingCountries maps the crown dependencies to the United Kingdom, whose IBANs and BICs they use.
+var bankingCountries = map[string]string{
+	"GG": "GB",
+	"IM": "GB",
+	"JE": "GB",
+}
+
+func bankingCountry(country string) string {
+	if banking, ok := bankingCountries[country]; ok {
+		return banking
+	}
+	return country
+}
+
+// ValidateIBAN checks the check digits of the IBAN and, for the countries it knows, its length and
+// the structure of its BBAN. The IBAN must be of the country of the account, unless none is given.
+func ValidateIBAN(iban string, country string) error {
+	if len(iban) < minIbanLength || len(iban) > maxIbanLength {
+		return &FieldError{Field: "iban", Reason: fmt.Sprintf("should be from %d to %d characters long", minIbanLength, maxIbanLength)}
+	}
+	ibanCountry := iban[:2]
+	if country != "" && ibanCountry != bankingCountry(country) {
+		return &FieldError{Field: "iban", Reason: fmt.Sprintf("is of country %s, not %s", ibanCountry, country)}
+	}
+	if format, ok := ibanFormats[ibanCountry]; ok {
+		if len(iban) != format.length {
+			return &FieldError{Field: "iban", Reason: fmt.Sprintf("should be %d characters long for country %s", format.length, ibanCountry)}
+		}
+		if !format.bban.MatchString(iban[4:]) {
+			return &FieldError{Field: "iban", Reason: fmt.Sprintf("does not have the account structure of country %s", ibanCountry)}
+		}
+	}
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return &FieldError{Field: "iban", Reason: "has invalid check digits"}
+	}
+	return nil
+}
+
+// ValidateBIC checks that the BIC is one of a bank in the country of the account.
+func ValidateBIC(bic string, country string) error {
+	if len(bic) < 6 {
+		return &FieldError{Field: "bic", Reason: "should be 8 or 11 characters long"}
+	}
+	if bicCountry := bic[4:6]; country != "" && bicCountry != bankingCountry(country) {
+		return &FieldError{Field: "bic", Reason: fmt.Sprintf("is of country %s, not %s", bicCountry, country)}
+	}
+	return nil
+}
+
+// GenerateIBAN builds the IBAN of an account of one of the countries whose BBAN can be built from
+// the bank identifier and account number: GB, DE, FR and ES. GB IBANs also take the bank code of the
+// BIC. It returns an empty IBAN when the country is not one of those or an identifier is missing,
+// and an error when an identifier does not fit the BBAN of the country.
+func GenerateIBAN(country string, bankId string, accountNumber string, bic string) (string, error) {
+	if bankId == "" || accountNumber == "" {
+		return "", nil
+	}
+	var bban string
+	var err error
+	switch country {
+	case "GB":
+		if len(bic) < 4 {
+			return "", nil
+		}
+		bban, err = gbBBAN(bankId, accountNumber, bic[:4])
+	case "DE":
+		bban, err = deBBAN(bankId, accountNumber)
+	case "FR":
+		bban, err = frBBAN(bankId, accountNumber)
+	case "ES":
+		bban, err = esBBAN(bankId, accountNumber)
+	default:
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	checkDigits := 98 - mod97(bban+country+"00")
+	return fmt.Sprintf("%s%02d%s", country, checkDigits, bban), nil
+}
+
+var (
+	digits        = regexp.MustCompile(`^[0-9]+$`)
+	alphanumerics = regexp.MustCompile(`^[A-Z0-9]+$`)
+)
+
+func fixedDigits(field string, value string, length int) error {
+	if len(value) != length || !digits.MatchString(value) {
+		return &FieldError{Field: field, Reason: fmt.Sprintf("should be %d digits", length)}
+	}
+	return nil
+}
+
+// gbBBAN is the bank code of the BIC, the sort code and the account number.
+func gbBBAN(bankId string, accountNumber string, bankCode string) (string, error) {
+	if err := fixedDigits("bank_id", bankId, 6); err != nil {
+		return "", err
+	}
+	if err := fixedDigits("account_number", accountNumber, 8); err != nil {
+		return "", err
+	}
+	return bankCode + bankId + accountNumber, nil
+}
+
+// deBBAN is the Bankleitzahl and the account number, padded to 10 digits.
+func deBBAN(bankId string, accountNumber string) (string, error) {
+	if err := fixedDigits("bank_id", bankId, 8); err != nil {
+		return "", err
+	}
+	if len(accountNumber) > 10 || !digits.MatchString(accountNumber) {
+		return "", &FieldError{Field: "account_number", Reason: "should be up to 10 digits"}
+	}
+	return bankId + fmt.Sprintf("%010s", accountNumber), nil
+}
+
+// frBBAN is the bank and branch codes, the account number and the RIB key.
+func frBBAN(bankId string, accountNumber string) (string, error) {
+	if err := fixedDigits("bank_id", bankId, 10); err != nil {
+		return "", err
+	}
+	if len(accountNumber) != 11 || !alphanumerics.MatchString(accountNumber) {
+		return "", &FieldError{Field: "account_number", Reason: "should be 11 characters"}
+	}
+	bank, _ := strconv.ParseInt(bankId[:5], 10, 64)
+	branch, _ := strconv.ParseInt(bankId[5:], 10, 64)
+	account, _ := strconv.ParseInt(strings.Map(ribDigit, accountNumber), 10, 64)
+	key := 97 - (89*bank+15*branch+3*account)%97
+	return fmt.Sprintf("%s%s%02d", bankId, accountNumber, key), nil
+}
+
+// ribDigit replaces the letters of a French account number by the digits the RIB key uses.
+func ribDigit(r rune) rune {
+	switch {
+	case r >= 'A' && r <= 'I':
+		return '1' + r - 'A'
+	case r >= 'J' && r <= 'R':
+		return '1' + r - 'J'
+	case r >= 'S' && r <= 'Z':
+		return '2' + r - 'S'
+	}
+	return r
+}
+
+// esBBAN is the bank and branch codes, their control digit, that of the account number and the
+// account number.
+func esBBAN(bankId string, accountNumber string) (string, error) {
+	if err := fixedDigits("bank_id", bankId, 8); err != nil {
+		return "", err
+	}
+	if err := fixedDigits("account_number", accountNumber, 10); err != nil {
+		return "", err
+	}
+	return bankId + esControlDigit("00"+bankId) + esControlDigit(accountNumber) + accountNumber, nil
+}
+
+var esWeights = []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+
+func esControlDigit(value string) string {
+	sum := 0
+	for i, r := range value {
+		sum += int(r-'0') * esWeights[i]
+	}
+	digit := 11 - sum%11
+	switch digit {
+	case 11:
+		digit = 0
+	case 10:
+		digit = 1
+	}
+	return strconv.Itoa(digit)
+}
+
+// mod97 is the remainder of the value, with letters replaced by 10 to 35, divided by 97.
+func mod97(value string) int {
+	remainder := 0
+	for _, r := range value {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,82 @@
+package bankidentifiers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	tests := []struct {
+		iban    string
+		country string
+		field   string
+	}{
+		{iban: "GB29NWBK60161331926819", country: "GB"},
+		{iban: "GB29NWBK60161331926819", country: "JE"},
+		{iban: "DE89370400440532013000", country: "DE"},
+		{iban: "FR1420041010050500013M02606", country: "FR"},
+		{iban: "ES9121000418450200051332", country: "ES"},
+		{iban: "NO9386011117947", country: ""},
+		{iban: "GB28NWBK60161331926819", country: "GB", field: "iban"},
+		{iban: "GB29NWBK6016133192681", country: "GB", field: "iban"},
+		{iban: "GB29NWBK6016133192681X", country: "GB", field: "iban"},
+		{iban: "DE89370400440532013000", country: "GB", field: "iban"},
+		{iban: "GB29NWBK", country: "GB", field: "iban"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.iban, func(t *testing.T) {
+			err := ValidateIBAN(tt.iban, tt.country)
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.IsType(t, &FieldError{}, err) {
+				assert.Equal(t, tt.field, err.(*FieldError).Field)
+			}
+		})
+	}
+}
+
+func TestValidateBIC(t *testing.T) {
+	assert.NoError(t, ValidateBIC("NWBKGB22", "GB"))
+	assert.NoError(t, ValidateBIC("NWBKGB22XXX", "IM"))
+	assert.Error(t, ValidateBIC("DEUTDEFF", "GB"))
+}
+
+func TestGenerateIBAN(t *testing.T) {
+	tests := []struct {
+		country       string
+		bankId        string
+		accountNumber string
+		bic           string
+		expected      string
+		field         string
+	}{
+		{country: "GB", bankId: "601613", accountNumber: "31926819", bic: "NWBKGB22", expected: "GB29NWBK60161331926819"},
+		{country: "GB", bankId: "601613", accountNumber: "31926819", expected: ""},
+		{country: "GB", bankId: "60161", accountNumber: "31926819", bic: "NWBKGB22", field: "bank_id"},
+		{country: "GB", bankId: "601613", accountNumber: "3192681", bic: "NWBKGB22", field: "account_number"},
+		{country: "DE", bankId: "37040044", accountNumber: "532013000", expected: "DE89370400440532013000"},
+		{country: "DE", bankId: "37040044", accountNumber: "53201300000", field: "account_number"},
+		{country: "FR", bankId: "2004101005", accountNumber: "0500013M026", expected: "FR1420041010050500013M02606"},
+		{country: "FR", bankId: "20041", accountNumber: "0500013M026", field: "bank_id"},
+		{country: "ES", bankId: "21000418", accountNumber: "0200051332", expected: "ES9121000418450200051332"},
+		{country: "ES", bankId: "21000418", accountNumber: "020005133", field: "account_number"},
+		{country: "NO", bankId: "8601", accountNumber: "1117947", expected: ""},
+		{country: "DE", bankId: "37040044", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+tt.bankId+tt.accountNumber, func(t *testing.T) {
+			iban, err := GenerateIBAN(tt.country, tt.bankId, tt.accountNumber, tt.bic)
+			if tt.field != "" {
+				if assert.IsType(t, &FieldError{}, err) {
+					assert.Equal(t, tt.field, err.(*FieldError).Field)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, iban)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package commandhandlers
+
+import (
+	"context"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/events"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/convert"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
+	"github.com/google/uuid"
+)
+
+// dispatchAccountEvent sends the audit event of a change to an account, with the account as it was
+// before the change, if it existed, and as it is now. The change is stored by then, so a failure
+// to send the event is logged rather than failing the command.
+func dispatchAccountEvent(ctx *context.Context, accountStorage *storage.AccountStorage, eventBuilder *internalmodels.Form3EventBuilder, before *internalmodels.AccountRecord, id uuid.UUID) {
+	after, err := accountStorage.Get(id)
+	if err != nil {
+		log.WithContext(ctx).WithField("account_id", id.String()).Errorf("could not read account for audit event: %v", err)
+		return
+	}
+
+	eventBuilder.
+		RecordType(settings.AccountsRecordType).
+		RecordId(after.ID).
+		OrganisationId(after.OrganisationID).
+		Version(*after.Version).
+		AfterData(convert.FromAccountDataRecord(after))
+	if before != nil {
+		eventBuilder.BeforeData(convert.FromAccountDataRecord(before))
+	}
+
+	event, err := eventBuilder.Build(ctx)
+	if err != nil {
+		log.WithContext(ctx).WithField("account_id", id.String()).Errorf("could not build audit event: %v", err)
+		return
+	}
+	err = executors.InMemoryEventDispatcher.Dispatch(ctx, events.Form3EventNotificationEvent{
+		Event: event,
+	})
+	if err != nil {
+		log.WithContext(ctx).WithField("account_id", id.String()).Errorf("could not dispatch audit event: %v", err)
+	}
+}
@@ -1,18 +1,42 @@
 package commandhandlers
 
 import (
+	"context"
+
 	"github.com/form3tech/go-security/security"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/google/uuid"
 )
 
 func Configure() {
 	errors.Must(executors.InMemoryCommandExecutor.RegisterCommandHandler(
 		CreateAccountCommandHandler,
-		security.AllowEveryone(),
+		security.RestrictWithPermissions(settings.AccountsRecordType, security.CREATE),
+	))
+	// The accounts of a bulk create may be of several organisations, whose permissions the
+	// handler checks one account at a time.
+	errors.Must(executors.InMemoryCommandExecutor.RegisterCommandHandler(
+		CreateAccountsCommandHandler,
+		func(ctx *context.Context, _ *uuid.UUID) error {
+			return security.CheckPermissionForResourceWithoutOrganisation(ctx, settings.AccountsRecordType, security.CREATE)
+		},
+	))
+	errors.Must(executors.InMemoryCommandExecutor.RegisterCommandHandler(
+		UpdateAccountCommandHandler,
+		security.RestrictWithPermissions(settings.AccountsRecordType, security.EDIT),
 	))
 	errors.Must(executors.InMemoryCommandExecutor.RegisterCommandHandler(
 		DeleteAccountCommandHandler,
-		security.AllowEveryone(),
+		security.RestrictWithPermissions(settings.AccountsRecordType, security.DELETE),
+	))
+	errors.Must(executors.InMemoryCommandExecutor.RegisterCommandHandler(
+		LockAccountCommandHandler,
+		security.RestrictWithPermissions(settings.AccountsRecordType, security.EDIT),
+	))
+	errors.Must(executors.InMemoryCommandExecutor.RegisterCommandHandler(
+		UnlockAccountCommandHandler,
+		security.RestrictWithPermissions(settings.AccountsRecordType, security.EDIT),
 	))
 }
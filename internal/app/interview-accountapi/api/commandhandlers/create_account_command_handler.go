@@ -2,9 +2,15 @@ package commandhandlers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/accountnumbers"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/bankidentifiers"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
 	"github.com/jmoiron/sqlx"
@@ -17,13 +23,108 @@ func CreateAccountCommandHandler(ctx *context.Context, db *sqlx.DB, c commands.C
 		Debug("Creating account...")
 
 	record := c.DataRecord
+	prepareNewAccount(record, time.Now().UTC())
+
+	var err error
+	if needsAccountNumber(record) {
+		err = inTransaction(db, func(tx *sqlx.Tx) error {
+			return insertAccount(tx, record)
+		})
+	} else {
+		err = insertAccount(db, record)
+	}
+	if err != nil {
+		return err
+	}
+	dispatchAccountEvent(ctx, storage.NewAccountStorage(db), internalmodels.NewForm3EventBuilder().Created(), nil, record.ID)
+	return nil
+}
+
+// prepareNewAccount sets what the service, rather than the caller, decides of a new account.
+func prepareNewAccount(record *internalmodels.AccountRecord, now time.Time) {
 	var defaultVersion int64 = 0
-	now := time.Now().UTC()
 	record.Version = &defaultVersion
 	record.CreatedOn = now
 	record.ModifiedOn = now
 	record.IsLocked = false
 	record.IsDeleted = false
+}
+
+func needsAccountNumber(record *internalmodels.AccountRecord) bool {
+	return record.Record.AccountNumber == "" && record.Record.Country != nil
+}
+
+// insertAccount stores a new account, after giving it an account number if it has none and
+// deriving its IBAN. An account number is only used up by an account that is created, so one
+// without a number must be inserted in a transaction.
+func insertAccount(db storage.Database, record *internalmodels.AccountRecord) error {
+	accountStorage := storage.NewAccountStorage(db)
+	if needsAccountNumber(record) {
+		if err := generateAccountNumber(accountStorage, storage.NewAccountNumberStorage(db), record); err != nil {
+			return err
+		}
+	}
+	if err := deriveIBAN(record); err != nil {
+		return err
+	}
+	return accountStorage.Create(record)
+}
+
+// inTransaction runs fn in a transaction, which it commits unless fn fails.
+func inTransaction(db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// maxAllocationAttempts bounds the sequence numbers taken for one account, which are only
+// skipped when they have no check digit or were given to an account by hand.
+const maxAllocationAttempts = 100
+
+// generateAccountNumber gives the account the next free number of its bank id, in the format of
+// its country.
+func generateAccountNumber(accountStorage *storage.AccountStorage, numberStorage *storage.AccountNumberStorage, record *internalmodels.AccountRecord) error {
+	country := *record.Record.Country
+	scheme := accountnumbers.SchemeOf(country)
+	for attempt := 0; attempt < maxAllocationAttempts; attempt++ {
+		sequence, err := numberStorage.Allocate(record.OrganisationID, country, record.Record.BankID, int64(settings.AccountNumberFirstSequence))
+		if err != nil {
+			return err
+		}
+		if sequence > scheme.MaxSequence() {
+			return errors.NewConflictError(fmt.Sprintf("account numbers of bank_id %s are exhausted", record.Record.BankID))
+		}
+		number, ok := scheme.Number(record.Record.BankID, sequence)
+		if !ok {
+			continue
+		}
+		taken, err := accountStorage.HasAccountNumber(record.OrganisationID, country, record.Record.BankID, number)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			record.Record.AccountNumber = number
+			return nil
+		}
+	}
+	return errors.NewConflictError(fmt.Sprintf("no free account number found under bank_id %s", record.Record.BankID))
+}
 
-	return storage.NewAccountStorage(db).Create(record)
+// deriveIBAN builds the IBAN of the account from its other identifiers when it is not given.
+func deriveIBAN(record *internalmodels.AccountRecord) error {
+	if record.Record.Iban != "" || record.Record.Country == nil {
+		return nil
+	}
+	iban, err := bankidentifiers.GenerateIBAN(*record.Record.Country, record.Record.BankID, record.Record.AccountNumber, record.Record.Bic)
+	if err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	record.Record.Iban = iban
+	return nil
 }
@@ -0,0 +1,156 @@
+package commandhandlers
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/form3tech/go-security/security"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
+	"github.com/jmoiron/sqlx"
+)
+
+// errAccountFailed rolls back the transaction of an atomic command once one of its accounts failed.
+var errAccountFailed = goerrors.New("an account could not be created")
+
+// CreateAccountsCommandHandler creates the accounts of the command in batches, each in a
+// transaction of its own, or all in one transaction when the command is atomic. Each account is
+// inserted under a savepoint, so that one that fails is undone alone and the others of its batch
+// are kept. An error other than one about the account itself fails the command, though batches
+// committed by then stay created.
+func CreateAccountsCommandHandler(ctx *context.Context, db *sqlx.DB, c commands.CreateAccountsCommand) error {
+	log.
+		WithContext(ctx).
+		WithField("accounts", len(c.DataRecords)).
+		Debug("Creating accounts...")
+
+	if c.Atomic {
+		return createAccountsAtomically(ctx, db, c)
+	}
+	for start := 0; start < len(c.DataRecords); start += settings.BulkCreateBatchSize {
+		end := start + settings.BulkCreateBatchSize
+		if end > len(c.DataRecords) {
+			end = len(c.DataRecords)
+		}
+		records, outcomes := c.DataRecords[start:end], c.Outcomes[start:end]
+		err := inTransaction(db, func(tx *sqlx.Tx) error {
+			return insertAccounts(ctx, tx, records, outcomes)
+		})
+		if err != nil {
+			return err
+		}
+		dispatchCreatedEvents(ctx, db, records, outcomes)
+	}
+	return nil
+}
+
+func createAccountsAtomically(ctx *context.Context, db *sqlx.DB, c commands.CreateAccountsCommand) error {
+	if anyFailed(c.Outcomes) {
+		rollBack(c.Outcomes)
+		return nil
+	}
+	err := inTransaction(db, func(tx *sqlx.Tx) error {
+		if err := insertAccounts(ctx, tx, c.DataRecords, c.Outcomes); err != nil {
+			return err
+		}
+		if anyFailed(c.Outcomes) {
+			return errAccountFailed
+		}
+		return nil
+	})
+	if err == errAccountFailed {
+		rollBack(c.Outcomes)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	dispatchCreatedEvents(ctx, db, c.DataRecords, c.Outcomes)
+	return nil
+}
+
+// insertAccounts inserts each account that has no outcome yet on tx and records its outcome.
+func insertAccounts(ctx *context.Context, tx *sqlx.Tx, records []*internalmodels.AccountRecord, outcomes []commands.CreateAccountOutcome) error {
+	now := time.Now().UTC()
+	for i, record := range records {
+		if record == nil || outcomes[i].Status != "" {
+			continue
+		}
+		if err := security.CheckPermission(ctx, settings.AccountsRecordType, &record.OrganisationID, security.CREATE); err != nil {
+			outcomes[i] = commands.CreateAccountOutcome{
+				Status: commands.AccountForbidden,
+				Reason: fmt.Sprintf("accounts of organisation %s cannot be created", record.OrganisationID),
+			}
+			continue
+		}
+		prepareNewAccount(record, now)
+
+		if _, err := tx.Exec("SAVEPOINT new_account"); err != nil {
+			return err
+		}
+		if err := insertAccount(tx, record); err != nil {
+			outcome, ok := outcomeOf(err)
+			if !ok {
+				return err
+			}
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT new_account"); err != nil {
+				return err
+			}
+			outcomes[i] = outcome
+			continue
+		}
+		if _, err := tx.Exec("RELEASE SAVEPOINT new_account"); err != nil {
+			return err
+		}
+		outcomes[i] = commands.CreateAccountOutcome{Status: commands.AccountCreated}
+	}
+	return nil
+}
+
+// outcomeOf tells what became of an account that could not be inserted, if the error is about
+// the account rather than the database.
+func outcomeOf(err error) (commands.CreateAccountOutcome, bool) {
+	switch err.(type) {
+	case *errors.DuplicateError:
+		return commands.CreateAccountOutcome{Status: commands.AccountDuplicate, Reason: err.Error()}, true
+	case *errors.IllegalArgumentError, *errors.ConflictError:
+		return commands.CreateAccountOutcome{Status: commands.AccountInvalid, Reason: err.Error()}, true
+	}
+	return commands.CreateAccountOutcome{}, false
+}
+
+func anyFailed(outcomes []commands.CreateAccountOutcome) bool {
+	for _, outcome := range outcomes {
+		if outcome.Status != "" && outcome.Status != commands.AccountCreated {
+			return true
+		}
+	}
+	return false
+}
+
+// rollBack marks the accounts that did not fail themselves as rolled back with the others.
+func rollBack(outcomes []commands.CreateAccountOutcome) {
+	for i, outcome := range outcomes {
+		if outcome.Status == "" || outcome.Status == commands.AccountCreated {
+			outcomes[i] = commands.CreateAccountOutcome{
+				Status: commands.AccountRolledBack,
+				Reason: "not created as another account of the request could not be",
+			}
+		}
+	}
+}
+
+func dispatchCreatedEvents(ctx *context.Context, db *sqlx.DB, records []*internalmodels.AccountRecord, outcomes []commands.CreateAccountOutcome) {
+	accountStorage := storage.NewAccountStorage(db)
+	for i, record := range records {
+		if outcomes[i].Status == commands.AccountCreated {
+			dispatchAccountEvent(ctx, accountStorage, internalmodels.NewForm3EventBuilder().Created(), nil, record.ID)
+		}
+	}
+}
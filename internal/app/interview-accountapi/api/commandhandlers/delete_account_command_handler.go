@@ -2,6 +2,7 @@ package commandhandlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
@@ -17,8 +18,18 @@ func DeleteAccountCommandHandler(ctx *context.Context, db *sqlx.DB, c commands.D
 		Debug("Deleting account...")
 
 	dataRecord := &internalmodels.AccountRecord{
-		Version: &c.Version,
-		ID:      c.AccountId,
+		Version:    &c.Version,
+		ID:         c.AccountId,
+		ModifiedOn: time.Now().UTC(),
 	}
-	return storage.NewAccountStorage(db).Delete(dataRecord)
+	accountStorage := storage.NewAccountStorage(db)
+	before, err := accountStorage.Get(c.AccountId)
+	if err != nil {
+		return err
+	}
+	if err := accountStorage.Delete(dataRecord); err != nil {
+		return err
+	}
+	dispatchAccountEvent(ctx, accountStorage, internalmodels.NewForm3EventBuilder().Deleted(), before, c.AccountId)
+	return nil
 }
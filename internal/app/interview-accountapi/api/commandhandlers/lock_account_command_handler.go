@@ -0,0 +1,56 @@
+package commandhandlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
+	"github.com/jmoiron/sqlx"
+)
+
+func LockAccountCommandHandler(ctx *context.Context, db *sqlx.DB, c commands.LockAccountCommand) error {
+	log.
+		WithContext(ctx).
+		WithField("account_id", c.AccountId.String()).
+		Debug("Locking account...")
+
+	dataRecord := &internalmodels.AccountRecord{
+		Version:    &c.Version,
+		ID:         c.AccountId,
+		ModifiedOn: time.Now().UTC(),
+	}
+	return setLocked(ctx, storage.NewAccountStorage(db), dataRecord, true)
+}
+
+func UnlockAccountCommandHandler(ctx *context.Context, db *sqlx.DB, c commands.UnlockAccountCommand) error {
+	log.
+		WithContext(ctx).
+		WithField("account_id", c.AccountId.String()).
+		Debug("Unlocking account...")
+
+	dataRecord := &internalmodels.AccountRecord{
+		Version:    &c.Version,
+		ID:         c.AccountId,
+		ModifiedOn: time.Now().UTC(),
+	}
+	return setLocked(ctx, storage.NewAccountStorage(db), dataRecord, false)
+}
+
+func setLocked(ctx *context.Context, accountStorage *storage.AccountStorage, record *internalmodels.AccountRecord, locked bool) error {
+	before, err := accountStorage.Get(record.ID)
+	if err != nil {
+		return err
+	}
+	if err := accountStorage.SetLocked(record, locked); err != nil {
+		return err
+	}
+	description := "Account locked"
+	if !locked {
+		description = "Account unlocked"
+	}
+	dispatchAccountEvent(ctx, accountStorage, internalmodels.NewForm3EventBuilder().Updated().Description(description), before, record.ID)
+	return nil
+}
@@ -0,0 +1,33 @@
+package commandhandlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/commands"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
+	"github.com/jmoiron/sqlx"
+)
+
+func UpdateAccountCommandHandler(ctx *context.Context, db *sqlx.DB, c commands.UpdateAccountCommand) error {
+	log.
+		WithContext(ctx).
+		WithField("account_id", c.DataRecord.ID.String()).
+		Debug("Updating account...")
+
+	record := c.DataRecord
+	record.ModifiedOn = time.Now().UTC()
+
+	accountStorage := storage.NewAccountStorage(db)
+	before, err := accountStorage.Get(record.ID)
+	if err != nil {
+		return err
+	}
+	if err := accountStorage.Update(record); err != nil {
+		return err
+	}
+	dispatchAccountEvent(ctx, accountStorage, internalmodels.NewForm3EventBuilder().Updated(), before, record.ID)
+	return nil
+}
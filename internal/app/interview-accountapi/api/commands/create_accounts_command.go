@@ -0,0 +1,30 @@
+package commands
+
+import "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+
+type CreateAccountStatus string
+
+const (
+	AccountCreated    CreateAccountStatus = "created"
+	AccountDuplicate  CreateAccountStatus = "duplicate"
+	AccountInvalid    CreateAccountStatus = "invalid"
+	AccountForbidden  CreateAccountStatus = "forbidden"
+	AccountRolledBack CreateAccountStatus = "rolled_back"
+)
+
+// CreateAccountOutcome is what became of one of the accounts of a CreateAccountsCommand.
+type CreateAccountOutcome struct {
+	Status CreateAccountStatus
+	// Reason tells why the account was not created.
+	Reason string
+}
+
+// CreateAccountsCommand creates many accounts at once. Outcomes is as long as DataRecords and
+// shares its order. The handler fills in the outcome of each account it attempts, and leaves
+// those already filled in, such as invalid accounts, alone. When Atomic is set, the accounts are
+// only created if all of them can be.
+type CreateAccountsCommand struct {
+	DataRecords []*internalmodels.AccountRecord
+	Outcomes    []CreateAccountOutcome
+	Atomic      bool
+}
@@ -0,0 +1,13 @@
+package commands
+
+import "github.com/google/uuid"
+
+type LockAccountCommand struct {
+	AccountId uuid.UUID
+	Version   int64
+}
+
+type UnlockAccountCommand struct {
+	AccountId uuid.UUID
+	Version   int64
+}
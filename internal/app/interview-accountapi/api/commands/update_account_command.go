@@ -0,0 +1,7 @@
+package commands
+
+import "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+
+type UpdateAccountCommand struct {
+	DataRecord *internalmodels.AccountRecord
+}
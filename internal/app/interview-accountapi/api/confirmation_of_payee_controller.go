@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/queries"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-openapi/strfmt"
+)
+
+func HandleConfirmationOfPayee(ctx *context.Context, c *gin.Context) error {
+	getLogger(ctx, c).Debug("Handling confirmation of payee")
+
+	request := &models.ConfirmationOfPayeeRequest{}
+	if err := c.BindJSON(request); err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+	if request.Data == nil {
+		return errors.NewIllegalArgumentError("data is required")
+	}
+	if err := request.Validate(strfmt.NewFormats()); err != nil {
+		return errors.NewIllegalArgumentError(err.Error())
+	}
+
+	criteria := queries.ConfirmationOfPayeeCriteria{
+		BankId:                  *request.Data.BankID,
+		AccountNumber:           *request.Data.AccountNumber,
+		AccountType:             *request.Data.AccountType,
+		Name:                    *request.Data.Name,
+		SecondaryIdentification: request.Data.SecondaryIdentification,
+	}
+	result := &queries.ConfirmationOfPayeeResult{}
+	if err := executors.QueryExecutor.Execute(ctx, criteria, &result); err != nil {
+		return err
+	}
+
+	c.JSON(http.StatusOK, &models.ConfirmationOfPayeeResponse{Data: &models.ConfirmationOfPayeeOutcome{
+		Result:     string(result.Outcome),
+		ReasonCode: result.ReasonCode,
+		ActualName: result.ActualName,
+	}})
+	return nil
+}
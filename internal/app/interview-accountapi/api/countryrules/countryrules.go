@@ -0,0 +1,197 @@
+// Package countryrules checks the attributes of an account against the rules of its country: which
+// of the bank identifiers are required or forbidden, the format of the bank id and account number,
+// the bank id code that goes with the bank id and the currencies the account may be held in.
+package countryrules
+
+import (
+	"regexp"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/go-openapi/errors"
+)
+
+// Field is the rule for one attribute, named as in the API.
+type Field struct {
+	Required  bool
+	Forbidden bool
+	// Pattern is the format of the attribute, when it is given.
+	Pattern string
+	// Values are the values the attribute may take, when it is given.
+	Values []string
+}
+
+// Rules are the rules of a country, by attribute.
+type Rules map[string]Field
+
+// countries are the rules of each country. Accounts of other countries are not checked. The
+// account number is never required, as one is generated when it is not given.
+var countries = map[string]Rules{
+	"AU": {
+		"bank_id":        Field{Pattern: `^[0-9]{6}$`},
+		"bank_id_code":   Field{Values: []string{"AUBSB"}},
+		"bic":            Field{Required: true},
+		"iban":           Field{Forbidden: true},
+		"account_number": Field{Pattern: `^[0-9]{6,10}$`},
+		"base_currency":  Field{Values: []string{"AUD"}},
+	},
+	"BE": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{3}$`},
+		"bank_id_code":   Field{Values: []string{"BE"}},
+		"account_number": Field{Pattern: `^[0-9]{7}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"CA": {
+		"bank_id":        Field{Pattern: `^0[0-9]{8}$`},
+		"bank_id_code":   Field{Values: []string{"CACPA"}},
+		"bic":            Field{Required: true},
+		"iban":           Field{Forbidden: true},
+		"account_number": Field{Pattern: `^[0-9]{7,12}$`},
+		"base_currency":  Field{Values: []string{"CAD"}},
+	},
+	"CH": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{5}$`},
+		"bank_id_code":   Field{Values: []string{"CHBCC"}},
+		"account_number": Field{Pattern: `^[0-9]{12}$`},
+		"base_currency":  Field{Values: []string{"CHF"}},
+	},
+	"DE": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{8}$`},
+		"bank_id_code":   Field{Values: []string{"DEBLZ"}},
+		"account_number": Field{Pattern: `^[0-9]{1,10}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"ES": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{8}$`},
+		"bank_id_code":   Field{Values: []string{"ESNCC"}},
+		"account_number": Field{Pattern: `^[0-9]{10}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"FR": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{10}$`},
+		"bank_id_code":   Field{Values: []string{"FR"}},
+		"account_number": Field{Pattern: `^[A-Z0-9]{11}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"GB": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{6}$`},
+		"bank_id_code":   Field{Values: []string{"GBDSC"}},
+		"account_number": Field{Pattern: `^[0-9]{8}$`},
+		"base_currency":  Field{Values: []string{"GBP"}},
+	},
+	"GR": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{7}$`},
+		"bank_id_code":   Field{Values: []string{"GRBIC"}},
+		"account_number": Field{Pattern: `^[0-9]{16}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"HK": {
+		"bank_id":        Field{Pattern: `^[0-9]{3}$`},
+		"bank_id_code":   Field{Values: []string{"HKNCC"}},
+		"bic":            Field{Required: true},
+		"iban":           Field{Forbidden: true},
+		"account_number": Field{Pattern: `^[0-9]{9,12}$`},
+		"base_currency":  Field{Values: []string{"HKD"}},
+	},
+	"IT": {
+		"bank_id":        Field{Required: true, Pattern: `^[A-Z0-9]{10,11}$`},
+		"bank_id_code":   Field{Values: []string{"ITNCC"}},
+		"account_number": Field{Pattern: `^[0-9]{12}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"LU": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{3}$`},
+		"bank_id_code":   Field{Values: []string{"LULUX"}},
+		"account_number": Field{Pattern: `^[A-Z0-9]{13}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"NL": {
+		"bank_id":        Field{Forbidden: true},
+		"bank_id_code":   Field{Forbidden: true},
+		"bic":            Field{Required: true},
+		"account_number": Field{Pattern: `^[0-9]{10}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"PL": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{8}$`},
+		"bank_id_code":   Field{Values: []string{"PLKNR"}},
+		"account_number": Field{Pattern: `^[0-9]{16}$`},
+		"base_currency":  Field{Values: []string{"PLN"}},
+	},
+	"PT": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{8}$`},
+		"bank_id_code":   Field{Values: []string{"PTNCC"}},
+		"account_number": Field{Pattern: `^[0-9]{11}$`},
+		"base_currency":  Field{Values: []string{"EUR"}},
+	},
+	"US": {
+		"bank_id":        Field{Required: true, Pattern: `^[0-9]{9}$`},
+		"bank_id_code":   Field{Values: []string{"USABA"}},
+		"bic":            Field{Required: true},
+		"iban":           Field{Forbidden: true},
+		"account_number": Field{Pattern: `^[0-9]{6,17}$`},
+		"base_currency":  Field{Values: []string{"USD"}},
+	},
+}
+
+// Validate checks the attributes against the rules of their country and returns every attribute
+// that breaks them.
+func Validate(attributes *models.AccountAttributes) error {
+	if attributes == nil || attributes.Country == nil {
+		return nil
+	}
+	rules, ok := countries[*attributes.Country]
+	if !ok {
+		return nil
+	}
+
+	values := map[string]string{
+		"bank_id":        attributes.BankID,
+		"bank_id_code":   attributes.BankIDCode,
+		"bic":            attributes.Bic,
+		"iban":           attributes.Iban,
+		"account_number": attributes.AccountNumber,
+		"base_currency":  attributes.BaseCurrency,
+	}
+	var res []error
+	for _, name := range []string{"bank_id", "bank_id_code", "bic", "iban", "account_number", "base_currency"} {
+		if err := rules[name].check(name, values[name], *attributes.Country); err != nil {
+			res = append(res, err)
+		}
+	}
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (f Field) check(name string, value string, country string) error {
+	if value == "" {
+		if f.Required {
+			return errors.Required(name, "body")
+		}
+		return nil
+	}
+	if f.Forbidden {
+		return errors.New(errors.UnallowedPropertyCode, "%s in body is not allowed for country %s", name, country)
+	}
+	if f.Pattern != "" && !regexp.MustCompile(f.Pattern).MatchString(value) {
+		return errors.FailedPattern(name, "body", f.Pattern)
+	}
+	if len(f.Values) > 0 && !contains(f.Values, value) {
+		values := make([]interface{}, len(f.Values))
+		for i, v := range f.Values {
+			values[i] = v
+		}
+		return errors.EnumFail(name, "body", value, values)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package countryrules
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/go-openapi/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		country    string
+		attributes models.AccountAttributes
+		invalid    []string
+	}{
+		{
+			name:       "valid GB account",
+			country:    "GB",
+			attributes: models.AccountAttributes{BankID: "400300", BankIDCode: "GBDSC", AccountNumber: "41426819", BaseCurrency: "GBP"},
+		},
+		{
+			name:       "GB account without a sort code",
+			country:    "GB",
+			attributes: models.AccountAttributes{AccountNumber: "41426819"},
+			invalid:    []string{"bank_id"},
+		},
+		{
+			name:       "GB account with a German bank id code, short account number and euros",
+			country:    "GB",
+			attributes: models.AccountAttributes{BankID: "400300", BankIDCode: "DEBLZ", AccountNumber: "4142681", BaseCurrency: "EUR"},
+			invalid:    []string{"bank_id_code", "account_number", "base_currency"},
+		},
+		{
+			name:       "GB account with a short sort code",
+			country:    "GB",
+			attributes: models.AccountAttributes{BankID: "40030"},
+			invalid:    []string{"bank_id"},
+		},
+		{
+			name:       "NL account with a bank id",
+			country:    "NL",
+			attributes: models.AccountAttributes{BankID: "400300", Bic: "ABNANL2A"},
+			invalid:    []string{"bank_id"},
+		},
+		{
+			name:       "US account without a BIC and with an IBAN",
+			country:    "US",
+			attributes: models.AccountAttributes{BankID: "021000021", Iban: "GB16NWBK40030041426819"},
+			invalid:    []string{"bic", "iban"},
+		},
+		{
+			name:       "DE account",
+			country:    "DE",
+			attributes: models.AccountAttributes{BankID: "37040044", BankIDCode: "DEBLZ", AccountNumber: "532013000"},
+		},
+		{
+			name:       "country without rules",
+			country:    "NO",
+			attributes: models.AccountAttributes{BankID: "anything"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.attributes.Country = &tt.country
+
+			err := Validate(&tt.attributes)
+			if len(tt.invalid) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			composite, ok := err.(*errors.CompositeError)
+			if !assert.True(t, ok, "expected a composite error but got %v", err) {
+				return
+			}
+			if assert.Len(t, composite.Errors, len(tt.invalid)) {
+				for i, field := range tt.invalid {
+					assert.Contains(t, composite.Errors[i].Error(), field+" in body")
+				}
+			}
+		})
+	}
+}
+
+func TestRulePatternsCompile(t *testing.T) {
+	for country, rules := range countries {
+		for name, field := range rules {
+			if field.Pattern != "" {
+				_, err := regexp.Compile(field.Pattern)
+				assert.NoError(t, err, "%s %s", country, name)
+			}
+		}
+	}
+}
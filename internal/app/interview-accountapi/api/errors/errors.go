@@ -89,3 +89,17 @@ type ConflictError struct {
 func (e *ConflictError) Error() string {
 	return e.message
 }
+
+type LockedError struct {
+	message string
+}
+
+func (e *LockedError) Error() string {
+	return e.message
+}
+
+func NewLockedError(message string) *LockedError {
+	return &LockedError{
+		message: message,
+	}
+}
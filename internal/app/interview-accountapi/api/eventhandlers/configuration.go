@@ -1,8 +1,12 @@
 package eventhandlers
 
 import (
+	"fmt"
+
 	"github.com/form3tech/go-messaging/messaging"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/eventsinks"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
 )
 
 const (
@@ -11,9 +15,27 @@ const (
 
 var form3EventSender messaging.Sender
 
+// Form3EventSender returns the sender Form3 events are published with, as selected by EVENT_SINK.
+func Form3EventSender() messaging.Sender {
+	return form3EventSender
+}
+
+// SetForm3EventSender replaces the sender selected by EVENT_SINK, for a sender whose events are
+// read in the same process.
+func SetForm3EventSender(sender messaging.Sender) {
+	form3EventSender = sender
+}
+
 func Configure() {
 	var err error
-	form3EventSender = messaging.NewSnsSender()
+	form3EventSender, err = eventsinks.New(eventsinks.Config{
+		Sink:       settings.EventSink,
+		FilePath:   settings.EventSinkFile,
+		WebhookURL: settings.EventSinkWebhookURL,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("could not configure event sink, error: %v", err))
+	}
 	err = executors.InMemoryEventDispatcher.RegisterEventHandler(Form3EventNotificationEventHandler)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,35 @@
+package eventsinks
+
+import (
+	"fmt"
+
+	"github.com/form3tech/go-messaging/messaging"
+)
+
+// ChannelSender hands events to a consumer in the same process. It cannot be selected with
+// EVENT_SINK, as the server has no such consumer: it is installed with
+// eventhandlers.SetForm3EventSender by whoever reads Messages, such as the acceptance tests.
+type ChannelSender struct {
+	messages chan messaging.Message
+}
+
+func NewChannelSender(size int) *ChannelSender {
+	return &ChannelSender{
+		messages: make(chan messaging.Message, size),
+	}
+}
+
+// Messages is where the sent messages can be received from.
+func (s *ChannelSender) Messages() <-chan messaging.Message {
+	return s.messages
+}
+
+// Send never blocks the sender: it fails when nobody keeps up with receiving the messages.
+func (s *ChannelSender) Send(destination string, message messaging.Message) error {
+	select {
+	case s.messages <- message:
+		return nil
+	default:
+		return fmt.Errorf("could not send to %s, the event channel is full", destination)
+	}
+}
@@ -0,0 +1,34 @@
+package eventsinks
+
+import (
+	"fmt"
+
+	"github.com/form3tech/go-messaging/messaging"
+)
+
+const (
+	Sns     = "sns"
+	File    = "file"
+	Webhook = "webhook"
+)
+
+// Config selects where events are sent and holds the settings of that sink.
+type Config struct {
+	Sink       string
+	FilePath   string
+	WebhookURL string
+}
+
+// New returns the sender of the configured sink. Every sink implements messaging.Sender, so event
+// handlers send to them the same way they send to SNS.
+func New(config Config) (messaging.Sender, error) {
+	switch config.Sink {
+	case Sns:
+		return messaging.NewSnsSender(), nil
+	case File:
+		return NewFileSender(config.FilePath)
+	case Webhook:
+		return NewWebhookSender(config.WebhookURL)
+	}
+	return nil, fmt.Errorf("unknown event sink %q, expected one of %s, %s or %s", config.Sink, Sns, File, Webhook)
+}
@@ -0,0 +1,104 @@
+package eventsinks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/form3tech/go-messaging/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	Id string `json:"id"`
+}
+
+func TestNew_UnknownSink(t *testing.T) {
+	_, err := New(Config{Sink: "carrier-pigeon"})
+	assert.Error(t, err)
+}
+
+func TestNew_ChannelIsNotASink(t *testing.T) {
+	_, err := New(Config{Sink: "channel"})
+	assert.Error(t, err, "nothing would read the events of a channel sink")
+}
+
+func TestNew_WebhookWithoutURL(t *testing.T) {
+	_, err := New(Config{Sink: Webhook})
+	assert.Error(t, err)
+}
+
+func TestChannelSender_Send(t *testing.T) {
+	sender := NewChannelSender(1)
+
+	assert.NoError(t, sender.Send("form3-events", messaging.Message{Body: testEvent{Id: "1"}}))
+	assert.Error(t, sender.Send("form3-events", messaging.Message{Body: testEvent{Id: "2"}}), "expected a full channel to fail")
+
+	message := <-sender.Messages()
+	assert.Equal(t, testEvent{Id: "1"}, message.Body)
+}
+
+func TestFileSender_Send(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventsinks")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.jsonl")
+
+	sender, err := NewFileSender(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, sender.Send("form3-events", messaging.Message{Body: testEvent{Id: "1"}}))
+	assert.NoError(t, sender.Send("form3-events", messaging.Message{Body: testEvent{Id: "2"}}))
+	assert.NoError(t, sender.Close())
+
+	content, err := ioutil.ReadFile(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+	assert.JSONEq(t, `{"destination":"form3-events","body":{"id":"1"}}`, lines[0])
+	assert.JSONEq(t, `{"destination":"form3-events","body":{"id":"2"}}`, lines[1])
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	var received testEvent
+	var destination string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		destination = r.Header.Get(destinationHeader)
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender, err := NewWebhookSender(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, sender.Send("form3-events", messaging.Message{Body: testEvent{Id: "1"}}))
+	assert.Equal(t, "form3-events", destination)
+	assert.Equal(t, testEvent{Id: "1"}, received)
+}
+
+func TestWebhookSender_SendFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender, err := NewWebhookSender(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Error(t, sender.Send("form3-events", messaging.Message{Body: testEvent{Id: "1"}}))
+}
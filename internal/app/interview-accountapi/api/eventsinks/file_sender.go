@@ -0,0 +1,56 @@
+package eventsinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/form3tech/go-messaging/messaging"
+)
+
+// FileSender appends each event as one line of JSON to a file.
+type FileSender struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// fileRecord is a line of the file.
+type fileRecord struct {
+	Destination string                 `json:"destination"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Body        interface{}            `json:"body"`
+}
+
+func NewFileSender(path string) (*FileSender, error) {
+	if path == "" {
+		return nil, fmt.Errorf("a file path is required for the %s event sink", File)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open event file %s, error: %v", path, err)
+	}
+	return &FileSender{file: file}, nil
+}
+
+func (s *FileSender) Send(destination string, message messaging.Message) error {
+	line, err := json.Marshal(fileRecord{
+		Destination: destination,
+		Attributes:  message.MessageAttributes,
+		Body:        message.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("could not write event to %s, error: %v", s.file.Name(), err)
+	}
+	return nil
+}
+
+func (s *FileSender) Close() error {
+	return s.file.Close()
+}
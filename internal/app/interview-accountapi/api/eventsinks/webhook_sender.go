@@ -0,0 +1,62 @@
+package eventsinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/form3tech/go-messaging/messaging"
+)
+
+const (
+	destinationHeader     = "X-Event-Destination"
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// WebhookSender posts each event as JSON to a URL. The destination is sent in the
+// X-Event-Destination header.
+type WebhookSender struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewWebhookSender(webhookURL string) (*WebhookSender, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("an absolute url is required for the %s event sink, got %q", Webhook, webhookURL)
+	}
+	return &WebhookSender{
+		url:        webhookURL,
+		httpClient: &http.Client{Timeout: defaultWebhookTimeout},
+	}, nil
+}
+
+func (s *WebhookSender) Send(destination string, message messaging.Message) error {
+	body, err := json.Marshal(message.Body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(destinationHeader, destination)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not post event to %s, error: %v", s.url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("could not post event to %s, status: %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
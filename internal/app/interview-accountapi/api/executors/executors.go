@@ -1,6 +1,7 @@
 package executors
 
 import (
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/eventsinks"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
 	"github.com/form3tech/go-cqrs/cqrs"
@@ -18,6 +19,15 @@ var InMemoryEventDispatcher cqrs.EventDispatcher
 var QueryExecutor cqrs.QueryExecutor
 
 func Configure(db *sqlx.DB) {
+	InMemoryCommandExecutor = cqrs.GetInMemoryCommandExecutor(db)
+	QueryExecutor = cqrs.GetQueryExecutor(db)
+	InMemoryEventDispatcher = cqrs.GetInMemoryEventDispatcher()
+
+	// The queued executors run on SQS, so they are only set up when the service publishes to AWS.
+	if settings.EventSink != eventsinks.Sns {
+		return
+	}
+
 	messageVisibilityTimeout := viper.GetInt("MessageVisibilityTimeout")
 
 	Sender = messaging.NewSqsSender()
@@ -26,10 +36,6 @@ func Configure(db *sqlx.DB) {
 		WithVisibilityTimeout(int64(messageVisibilityTimeout)).
 		Build()
 
-	InMemoryCommandExecutor = cqrs.GetInMemoryCommandExecutor(db)
-	QueryExecutor = cqrs.GetQueryExecutor(db)
-	InMemoryEventDispatcher = cqrs.GetInMemoryEventDispatcher()
-
 	queuedExecutor := cqrs.GetQueuedCommandExecutor(db, settings.ApiName, Receiver, Sender)
 	queuedExecutor.QueueNamer = cqrs.SharedQueueNamingStrategy{ApplicationName: settings.ApiName, QueueName: "commands"}
 
@@ -0,0 +1,134 @@
+/* #nosec */// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"encoding/json"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// AccountMatch account match
+// swagger:model AccountMatch
+type AccountMatch struct {
+
+	// account
+	Account *Account `json:"account,omitempty"`
+
+	// How well the name matches, exact, close or none
+	// Enum: [exact close none]
+	MatchStrength string `json:"match_strength,omitempty"`
+
+	// The bank account name or alternative bank account name that matched best
+	MatchedName string `json:"matched_name,omitempty"`
+
+	// Similarity of the names, from 0 to 1
+	Score float64 `json:"score,omitempty"`
+}
+
+// Validate validates this account match
+func (m *AccountMatch) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateAccount(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateMatchStrength(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *AccountMatch) validateAccount(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Account) { // not required
+		return nil
+	}
+
+	if m.Account != nil {
+		if err := m.Account.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("account")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+var accountMatchTypeMatchStrengthPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["exact","close","none"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		accountMatchTypeMatchStrengthPropEnum = append(accountMatchTypeMatchStrengthPropEnum, v)
+	}
+}
+
+const (
+
+	// AccountMatchMatchStrengthExact captures enum value "exact"
+	AccountMatchMatchStrengthExact string = "exact"
+
+	// AccountMatchMatchStrengthClose captures enum value "close"
+	AccountMatchMatchStrengthClose string = "close"
+
+	// AccountMatchMatchStrengthNone captures enum value "none"
+	AccountMatchMatchStrengthNone string = "none"
+)
+
+// prop value enum
+func (m *AccountMatch) validateMatchStrengthEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, accountMatchTypeMatchStrengthPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *AccountMatch) validateMatchStrength(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.MatchStrength) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateMatchStrengthEnum("match_strength", "body", m.MatchStrength); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *AccountMatch) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *AccountMatch) UnmarshalBinary(b []byte) error {
+	var res AccountMatch
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
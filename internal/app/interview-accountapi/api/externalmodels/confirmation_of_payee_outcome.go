@@ -0,0 +1,115 @@
+/* #nosec */// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"encoding/json"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// ConfirmationOfPayeeOutcome confirmation of payee outcome
+// swagger:model ConfirmationOfPayeeOutcome
+type ConfirmationOfPayeeOutcome struct {
+
+	// Name of the account, only given on a close match
+	ActualName string `json:"actual_name,omitempty"`
+
+	// Confirmation of Payee reason code, e.g. MBAM for a close match or AC01 for an account not found. Not given on a match.
+	ReasonCode string `json:"reason_code,omitempty"`
+
+	// Outcome of the check
+	// Enum: [match close_match no_match account_not_found opted_out]
+	Result string `json:"result,omitempty"`
+}
+
+// Validate validates this confirmation of payee outcome
+func (m *ConfirmationOfPayeeOutcome) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateResult(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+var confirmationOfPayeeOutcomeTypeResultPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["match","close_match","no_match","account_not_found","opted_out"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		confirmationOfPayeeOutcomeTypeResultPropEnum = append(confirmationOfPayeeOutcomeTypeResultPropEnum, v)
+	}
+}
+
+const (
+
+	// ConfirmationOfPayeeOutcomeResultMatch captures enum value "match"
+	ConfirmationOfPayeeOutcomeResultMatch string = "match"
+
+	// ConfirmationOfPayeeOutcomeResultCloseMatch captures enum value "close_match"
+	ConfirmationOfPayeeOutcomeResultCloseMatch string = "close_match"
+
+	// ConfirmationOfPayeeOutcomeResultNoMatch captures enum value "no_match"
+	ConfirmationOfPayeeOutcomeResultNoMatch string = "no_match"
+
+	// ConfirmationOfPayeeOutcomeResultAccountNotFound captures enum value "account_not_found"
+	ConfirmationOfPayeeOutcomeResultAccountNotFound string = "account_not_found"
+
+	// ConfirmationOfPayeeOutcomeResultOptedOut captures enum value "opted_out"
+	ConfirmationOfPayeeOutcomeResultOptedOut string = "opted_out"
+)
+
+// prop value enum
+func (m *ConfirmationOfPayeeOutcome) validateResultEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, confirmationOfPayeeOutcomeTypeResultPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ConfirmationOfPayeeOutcome) validateResult(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Result) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateResultEnum("result", "body", m.Result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *ConfirmationOfPayeeOutcome) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *ConfirmationOfPayeeOutcome) UnmarshalBinary(b []byte) error {
+	var res ConfirmationOfPayeeOutcome
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -2,19 +2,87 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"github.com/form3tech/go-security/security"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
 	models "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/externalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
+	"github.com/form3tech-oss/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	pkgerr "github.com/pkg/errors"
 	"net/http"
 )
 
+// tokenReader validates the bearer token of each request. It is nil when authentication is
+// disabled, in which case requests are not authenticated.
+var tokenReader security.JwtTokenParser
+
+func configureAuthentication() {
+	tokenReader = newTokenReader(settings.JwtPublicKey, settings.AuthDisabled)
+}
+
+// newTokenReader returns the reader of tokens signed with the key. Without a key the server
+// refuses to start, unless authentication is explicitly disabled, as it would otherwise serve the
+// accounts of every organisation to anyone.
+func newTokenReader(jwtPublicKey string, authDisabled bool) security.JwtTokenParser {
+	if jwtPublicKey == "" {
+		if !authDisabled {
+			panic("JWT_PUBLIC_KEY must be set, or AUTH_DISABLED set to true to serve requests without authentication")
+		}
+		log.Warn("AUTH_DISABLED is set, requests will not be authenticated")
+		return nil
+	}
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(jwtPublicKey))
+	if err != nil {
+		panic(fmt.Sprintf("could not parse JWT_PUBLIC_KEY, error: %v", err))
+	}
+	return security.GetTokenReader(publicKey)
+}
+
+// userContext returns the context holding the ACLs and user id of the bearer token of the request.
+// Without authentication every request runs with the rights of the application. A token whose user
+// id is missing or not a uuid is refused, as the audit events of the changes it makes name the user.
+func userContext(req *http.Request) (*context.Context, error) {
+	if tokenReader == nil {
+		return security.ApplicationContext(req.Context()), nil
+	}
+	ctx, err := parseToken(req)
+	if err != nil {
+		return nil, err
+	}
+	userId, err := security.GetUserIDFromContext(*ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, fmt.Errorf("user id %q of the token is not a uuid", userId)
+	}
+	userCtx := context.WithValue(*ctx, "user_id", userId)
+	return &userCtx, nil
+}
+
+// parseToken reads the bearer token of the request. The token reader panics on a token without a
+// user id, which is taken for an invalid token instead.
+func parseToken(req *http.Request) (ctx *context.Context, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ctx, err = nil, fmt.Errorf("could not read jwt token claims: %v", r)
+		}
+	}()
+	return tokenReader.ParseTokenFromRequest(req)
+}
+
 func WithUserContext(handler func(ctx *context.Context, c *gin.Context) error) func(ctx *gin.Context) {
 	return func(c *gin.Context) {
-		ctx := context.Background()
-		if err := handler(&ctx, c); err != nil {
+		ctx, err := userContext(c.Request)
+		if err != nil {
+			log.Infof("unauthorised request: %v", err)
+			c.JSON(http.StatusUnauthorized, models.APIError{ErrorMessage: "unauthorised"})
+			return
+		}
+		if err := handler(ctx, c); err != nil {
 			switch e := pkgerr.Cause(err).(type) {
 			case *security.AuthError:
 				log.Infof("%v", e)
@@ -40,6 +108,10 @@ func WithUserContext(handler func(ctx *context.Context, c *gin.Context) error) f
 				log.Infof("%v", e)
 				c.JSON(http.StatusConflict, models.APIError{ErrorMessage: e.Error()})
 				return
+			case *errors.LockedError:
+				log.Infof("%v", e)
+				c.JSON(http.StatusLocked, models.APIError{ErrorMessage: e.Error()})
+				return
 			case *errors.IllegalArgumentError:
 				log.Infof("%v", e)
 				c.JSON(http.StatusBadRequest, models.APIError{ErrorMessage: e.Error()})
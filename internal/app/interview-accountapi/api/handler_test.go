@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/form3tech/go-security/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTokenReader_RefusesToStartWithoutAKey(t *testing.T) {
+	assert.Panics(t, func() { newTokenReader("", false) })
+}
+
+func TestNewTokenReader_WithoutAuthentication(t *testing.T) {
+	assert.Nil(t, newTokenReader("", true))
+}
+
+func TestWithUserContext_RejectsRequestsWithoutATokenWhenAKeyIsSet(t *testing.T) {
+	keyPair, err := security.GenerateTestKeyPair()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func(previous security.JwtTokenParser) { tokenReader = previous }(tokenReader)
+	tokenReader = newTokenReader(keyPair.PublicKeyPem, true)
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/organisation/accounts", nil)
+	handled := false
+
+	WithUserContext(func(ctx *context.Context, c *gin.Context) error {
+		handled = true
+		return nil
+	})(c)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.False(t, handled)
+}
@@ -87,6 +87,15 @@ type AccountRecord struct {
 	ModifiedOn     time.Time   `db:"modified_on"`
 	Record         Account     `db:"record"`
 	PaginationId   int64       `db:"pagination_id"`
+	// NameKeys are the keys of the names of the account, which searches look accounts up by.
+	// Storage keeps them in step with the record.
+	NameKeys *string `db:"name_keys"`
+}
+
+// Names returns the names an account can be matched on: its bank account name followed by the
+// alternative ones.
+func (r Account) Names() []string {
+	return append([]string{r.BankAccountName}, r.AlternativeBankAccountNames...)
 }
 
 func (r *Account) Scan(src interface{}) error {
@@ -3,6 +3,7 @@ package internalmodels
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/form3tech/go-security/security"
@@ -39,6 +40,9 @@ func (b *Form3EventBuilder) Created() *Form3EventBuilder {
 func (b *Form3EventBuilder) Updated() *Form3EventBuilder {
 	return b.EventType("updated").Description("Record updated")
 }
+func (b *Form3EventBuilder) Deleted() *Form3EventBuilder {
+	return b.EventType("deleted").Description("Record deleted")
+}
 func (b *Form3EventBuilder) RecordId(id uuid.UUID) *Form3EventBuilder {
 	b.event.Record.RecordId = id
 	return b
@@ -80,11 +84,17 @@ func (b *Form3EventBuilder) BeforeData(data interface{}) *Form3EventBuilder {
 	}
 	return b
 }
-func (b *Form3EventBuilder) Build(ctx *context.Context) *Form3Event {
-	if security.IsApplicationContext(*ctx) {
-		b.event.Record.ActionedBy = uuid.MustParse(settings.UserID)
-	} else {
-		b.event.Record.ActionedBy = uuid.MustParse((*ctx).Value("user_id").(string))
+// Build returns the event, actioned by the user of the context. It fails when the context has no
+// user id that is a uuid.
+func (b *Form3EventBuilder) Build(ctx *context.Context) (*Form3Event, error) {
+	userId := settings.UserID
+	if !security.IsApplicationContext(*ctx) {
+		userId, _ = (*ctx).Value("user_id").(string)
+	}
+	actionedBy, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id %q: %v", userId, err)
 	}
-	return &b.event
+	b.event.Record.ActionedBy = actionedBy
+	return &b.event, nil
 }
@@ -0,0 +1,32 @@
+package internalmodels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestForm3EventBuilder_Build(t *testing.T) {
+	userId := uuid.New()
+	ctx := context.WithValue(context.Background(), "user_id", userId.String())
+
+	event, err := NewForm3EventBuilder().Build(&ctx)
+
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if event.Record.ActionedBy != userId {
+		t.Errorf("Build() actioned by %v, want %v", event.Record.ActionedBy, userId)
+	}
+}
+
+func TestForm3EventBuilder_BuildWithoutAUserId(t *testing.T) {
+	for _, userId := range []interface{}{nil, "", "admin"} {
+		ctx := context.WithValue(context.Background(), "user_id", userId)
+
+		if _, err := NewForm3EventBuilder().Build(&ctx); err == nil {
+			t.Errorf("Build() with user id %v succeeded, want an error", userId)
+		}
+	}
+}
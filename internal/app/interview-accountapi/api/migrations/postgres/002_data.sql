@@ -0,0 +1,60 @@
+-- +migrate Up
+
+INSERT INTO "Account"
+(id,organisation_id,version,is_deleted,is_locked,created_on,modified_on,record)
+VALUES (
+    md5(random()::text || clock_timestamp()::text)::uuid,
+    md5(random()::text || clock_timestamp()::text)::uuid,
+    0,
+    false,
+    false,
+    now(),
+    now(),
+    '{
+           "country": "GB",
+           "base_currency": "GBP",
+           "account_number": "41426819",
+           "bank_id": "400300",
+           "bank_id_code": "GBDSC",
+           "bic": "NWBKGB22",
+           "iban": "GB16NWBK40030041426819",
+           "title": "Ms",
+           "first_name": "Samantha",
+           "bank_account_name": "Samantha Holder",
+           "alternative_bank_account_names": [
+             "Sam Holder"
+           ],
+           "account_classification": "Personal",
+           "joint_account": false,
+           "account_matching_opt_out": false,
+           "secondary_identification": "A1B2C3D4"
+     }'),(
+    md5(random()::text || clock_timestamp()::text)::uuid,
+    md5(random()::text || clock_timestamp()::text)::uuid,
+    0,
+    false,
+    false,
+    now(),
+    now(),
+    '{
+           "country": "GB",
+           "base_currency": "GBP",
+           "account_number": "51426819",
+           "bank_id": "400300",
+           "bank_id_code": "GBDSC",
+           "bic": "NWBKGB22",
+           "iban": "GB16NWBK40030041426819",
+           "title": "Mr",
+           "first_name": "Barry",
+           "bank_account_name": "White",
+           "alternative_bank_account_names": [
+             "Baz White"
+           ],
+           "account_classification": "Personal",
+           "joint_account": false,
+           "account_matching_opt_out": false,
+           "secondary_identification": "JJZDEDE"
+     }');
+
+-- +migrate Down
+DELETE FROM "Account";
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE UNIQUE INDEX Account_identity ON "Account" (
+  organisation_id,
+  (record->>'country'),
+  (record->>'bank_id'),
+  (record->>'account_number')
+) WHERE NOT is_deleted;
+
+-- +migrate Down
+DROP INDEX IF EXISTS Account_identity;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- The account numbers generated for the accounts of an organisation under a bank id are taken from
+-- its sequence. A row inserted ahead of time makes them start at next_sequence and, when
+-- last_sequence is set, stop at it, which keeps them in a range set aside for the organisation.
+CREATE TABLE IF NOT EXISTS "AccountNumberAllocation"
+(
+  organisation_id UUID        NOT NULL,
+  country         VARCHAR(2)  NOT NULL,
+  bank_id         VARCHAR(20) NOT NULL,
+  next_sequence   BIGINT      NOT NULL,
+  last_sequence   BIGINT,
+  PRIMARY KEY (organisation_id, country, bank_id)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS "AccountNumberAllocation";
@@ -17,7 +17,7 @@ VALUES (
            "bank_id": "400300",
            "bank_id_code": "GBDSC",
            "bic": "NWBKGB22",
-           "iban": "GB11NWBK40030041426819",
+           "iban": "GB16NWBK40030041426819",
            "title": "Ms",
            "first_name": "Samantha",
            "bank_account_name": "Samantha Holder",
@@ -43,7 +43,7 @@ VALUES (
            "bank_id": "400300",
            "bank_id_code": "GBDSC",
            "bic": "NWBKGB22",
-           "iban": "GB11NWBK40030041426819",
+           "iban": "GB16NWBK40030041426819",
            "title": "Mr",
            "first_name": "Barry",
            "bank_account_name": "White",
@@ -57,4 +57,4 @@ VALUES (
      }');
 
 -- +migrate Down
-DELETE FROM "Accounts";
\ No newline at end of file
+DELETE FROM "Account";
\ No newline at end of file
@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE UNIQUE INDEX Account_identity ON "Account" (
+  organisation_id,
+  json_extract(CAST(record AS TEXT), '$.country'),
+  json_extract(CAST(record AS TEXT), '$.bank_id'),
+  json_extract(CAST(record AS TEXT), '$.account_number')
+) WHERE NOT is_deleted;
+
+-- +migrate Down
+DROP INDEX IF EXISTS Account_identity;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- The keys of the names of an account, which searches by name look accounts up by before scoring
+-- them. They are computed by the API, which fills them in on startup for the accounts stored
+-- before this column.
+ALTER TABLE "Account" ADD COLUMN name_keys TEXT;
+
+-- +migrate Down
+-- SQLite only drops columns from 3.35 on, so the column is left in place.
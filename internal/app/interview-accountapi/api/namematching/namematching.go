@@ -0,0 +1,248 @@
+// Package namematching compares account names the way a Confirmation of Payee responder does:
+// names are normalised first, so that case, accents, punctuation and titles do not matter, and
+// then scored on how similar they are.
+package namematching
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+type Strength string
+
+const (
+	Exact Strength = "exact"
+	Close Strength = "close"
+	None  Strength = "none"
+)
+
+// closeThreshold is the similarity from which two names that differ are a close match.
+const closeThreshold = 0.9
+
+// ignoredWords are titles, which payers rarely get right and which do not tell names apart.
+var ignoredWords = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true, "dr": true, "prof": true,
+	"sir": true, "dame": true, "lord": true, "lady": true, "rev": true,
+}
+
+// synonyms are spelled the same way before comparing names.
+var synonyms = map[string]string{
+	"&":       "and",
+	"limited": "ltd",
+	"company": "co",
+}
+
+// keyLength is the number of letters of a word that make up its key.
+const keyLength = 2
+
+// Result is how well a name matches the best of the names it was compared to.
+type Result struct {
+	Strength Strength
+	Score    float64
+	// Name is the name that matched best, as it was given, or empty when none was given.
+	Name string
+}
+
+// Match compares name to each of names and returns the best match.
+func Match(name string, names ...string) Result {
+	best := Result{Strength: None}
+	normalised := Normalise(name)
+	for _, candidate := range names {
+		if candidate == "" {
+			continue
+		}
+		result := compare(normalised, Normalise(candidate))
+		result.Name = candidate
+		if best.Name == "" || result.Better(best) {
+			best = result
+		}
+	}
+	return best
+}
+
+// Keys returns what names are looked up by before being scored: the first letters of each word
+// of the normalised names, in order and without repeats. Names that match share a key, unless a
+// typo hits the start of every one of their words, so only the names sharing a key with a name
+// need to be scored against it.
+func Keys(names ...string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, name := range names {
+		for _, word := range strings.Fields(Normalise(name)) {
+			key := word
+			if runes := []rune(word); len(runes) > keyLength {
+				key = string(runes[:keyLength])
+			}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Better reports whether r is a stronger match than other, or as strong a match with a higher score.
+func (r Result) Better(other Result) bool {
+	return r.rank() > other.rank()
+}
+
+func (r Result) rank() float64 {
+	switch r.Strength {
+	case Exact:
+		return 3 + r.Score
+	case Close:
+		return 2 + r.Score
+	}
+	return r.Score
+}
+
+func compare(a string, b string) Result {
+	if a == "" || b == "" {
+		return Result{Strength: None}
+	}
+	if a == b {
+		return Result{Strength: Exact, Score: 1}
+	}
+	score := Similarity(a, b)
+	if score >= closeThreshold || initialsMatch(strings.Fields(a), strings.Fields(b)) {
+		return Result{Strength: Close, Score: score}
+	}
+	return Result{Strength: None, Score: score}
+}
+
+// Normalise lower-cases the name, strips accents and punctuation, spells synonyms the same way and
+// leaves out titles.
+func Normalise(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// accents are separated from their letters by the decomposition
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		case r == '&':
+			b.WriteString(" & ")
+		case r == '\'':
+			// O'Brien is OBrien rather than O Brien
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	var words []string
+	for _, word := range strings.Fields(b.String()) {
+		if synonym, ok := synonyms[word]; ok {
+			word = synonym
+		}
+		if !ignoredWords[word] {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Similarity scores two normalised names from 0 to 1 with the Jaro-Winkler similarity. Names with
+// as many words are also compared word by word, in alphabetical order, so that "Holder Samantha"
+// is like "Samantha Holder".
+func Similarity(a string, b string) float64 {
+	score := jaroWinkler(a, b)
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	if len(wordsA) != len(wordsB) || len(wordsA) < 2 {
+		return score
+	}
+	sort.Strings(wordsA)
+	sort.Strings(wordsB)
+	total := 0.0
+	for i := range wordsA {
+		total += jaroWinkler(wordsA[i], wordsB[i])
+	}
+	if byWord := total / float64(len(wordsA)); byWord > score {
+		score = byWord
+	}
+	return score
+}
+
+// initialsMatch reports whether both names have the same surname, the last word, and the other
+// words are either the same or given as initials on one side, as in "S Holder" and "Samantha Holder".
+func initialsMatch(a []string, b []string) bool {
+	if len(a) < 2 || len(a) != len(b) || a[len(a)-1] != b[len(b)-1] {
+		return false
+	}
+	for i := 0; i < len(a)-1; i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		short, long := a[i], b[i]
+		if len(short) > len(long) {
+			short, long = long, short
+		}
+		if len([]rune(short)) != 1 || !strings.HasPrefix(long, short) {
+			return false
+		}
+	}
+	return true
+}
+
+func jaroWinkler(a string, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	jaro := jaroSimilarity(s1, s2)
+
+	prefix := 0
+	for prefix < len(s1) && prefix < len(s2) && prefix < 4 && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+func jaroSimilarity(s1 []rune, s2 []rune) float64 {
+	if len(s1) == 0 && len(s2) == 0 {
+		return 1
+	}
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		from, to := max(0, i-window), min(len(s2), i+window+1)
+		for j := from; j < to; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions/2))/m) / 3
+}
@@ -0,0 +1,85 @@
+package namematching
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalise(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Samantha Holder", "samantha holder"},
+		{"  MS. Samantha   HOLDER ", "samantha holder"},
+		{"Dr Zoë O'Brien-Smith", "zoe obrien smith"},
+		{"Smith & Sons Limited", "smith and sons ltd"},
+		{"Mr", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalise(tt.name))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		strength Strength
+		matched  string
+	}{
+		{"Samantha Holder", []string{"Samantha Holder"}, Exact, "Samantha Holder"},
+		{"ms samantha holder", []string{"Samantha Holder"}, Exact, "Samantha Holder"},
+		{"Samanta Holder", []string{"Samantha Holder"}, Close, "Samantha Holder"},
+		{"Holder Samantha", []string{"Samantha Holder"}, Close, "Samantha Holder"},
+		{"S Holder", []string{"Samantha Holder"}, Close, "Samantha Holder"},
+		{"Sam Holder", []string{"Samantha Holder", "Sam Holder"}, Exact, "Sam Holder"},
+		{"Samantha Holdr", []string{"Barry White", "Samantha Holder"}, Close, "Samantha Holder"},
+		{"Barry White", []string{"Samantha Holder"}, None, "Samantha Holder"},
+		{"T Holder", []string{"Samantha Holder"}, None, "Samantha Holder"},
+		{"Samantha Holder", nil, None, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Match(tt.name, tt.names...)
+			assert.Equal(t, tt.strength, result.Strength)
+			assert.Equal(t, tt.matched, result.Name)
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	assert.Equal(t, []string{"ho", "sa"}, Keys("Ms Samantha Holder", "Sam Holder"))
+	assert.Equal(t, []string{"an", "sm", "so"}, Keys("Smith & Sons"))
+	assert.Equal(t, []string{"ho", "s"}, Keys("S. Holder"))
+	assert.Empty(t, Keys("Mr", ""))
+}
+
+func TestMatchesShareAKey(t *testing.T) {
+	for _, tt := range [][2]string{
+		{"Samanta Holder", "Samantha Holder"},
+		{"Holder Samantha", "Samantha Holder"},
+		{"S Holder", "Samantha Holder"},
+		{"Samantha Holdr", "Samantha Holder"},
+		{"Zoe OBrien", "Dr Zoë O'Brien"},
+	} {
+		assert.NotEqual(t, None, Match(tt[0], tt[1]).Strength, tt[0])
+		shared := false
+		for _, key := range Keys(tt[0]) {
+			for _, other := range Keys(tt[1]) {
+				shared = shared || key == other
+			}
+		}
+		assert.True(t, shared, tt[0])
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("martha", "martha"))
+	assert.InDelta(t, 0.961, Similarity("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, Similarity("dwayne", "duane"), 0.001)
+	assert.Equal(t, 0.0, Similarity("abc", "xyz"))
+}
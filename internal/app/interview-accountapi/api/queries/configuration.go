@@ -1,18 +1,52 @@
 package queries
 
 import (
+	"context"
+	"reflect"
+
 	"github.com/form3tech/go-cqrs/cqrs"
+	"github.com/form3tech/go-security/security"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/executors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
 )
 
 func Configure() {
 	errors.Must(executors.QueryExecutor.RegisterQuery(
 		GetAccountByIdQuery,
-		cqrs.WithNoFilter()),
+		withOrganisationFilter(settings.AccountsRecordType)),
 	)
+	// ListAccountsQuery only selects the organisations the user may read, so that pages stay full.
 	errors.Must(executors.QueryExecutor.RegisterQuery(
 		ListAccountsQuery,
 		cqrs.WithNoFilter()),
 	)
+	// Likewise SearchAccountsQuery only reads the organisations the user may read.
+	errors.Must(executors.QueryExecutor.RegisterQuery(
+		SearchAccountsQuery,
+		cqrs.WithNoFilter()),
+	)
+	// ExportAccountsQuery reads the organisations the user may read as ListAccountsQuery does.
+	errors.Must(executors.QueryExecutor.RegisterQuery(
+		ExportAccountsQuery,
+		cqrs.WithNoFilter()),
+	)
+	// ConfirmationOfPayeeQuery only looks for the account in the organisations the user may read.
+	errors.Must(executors.QueryExecutor.RegisterQuery(
+		ConfirmationOfPayeeQuery,
+		cqrs.WithNoFilter()),
+	)
+}
+
+// withOrganisationFilter rejects results of organisations the user may not read. The application
+// itself, which is what requests run as when authentication is off, may read every organisation.
+func withOrganisationFilter(recordType string) func(*context.Context, []reflect.Value, interface{}) error {
+	noFilter := cqrs.WithNoFilter()
+	organisationFilter := cqrs.WithOrganisationFilter(security.ProduceAuthError, security.QueryForRecordPermissions(recordType))
+	return func(ctx *context.Context, queryResult []reflect.Value, result interface{}) error {
+		if ctx != nil && security.IsApplicationContext(*ctx) {
+			return noFilter(ctx, queryResult, result)
+		}
+		return organisationFilter(ctx, queryResult, result)
+	}
 }
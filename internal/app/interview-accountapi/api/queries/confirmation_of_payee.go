@@ -0,0 +1,172 @@
+package queries
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/form3tech/go-data/data"
+	"github.com/form3tech/go-security/security"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/namematching"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PayeeOutcome string
+
+const (
+	PayeeMatch           PayeeOutcome = "match"
+	PayeeCloseMatch      PayeeOutcome = "close_match"
+	PayeeNoMatch         PayeeOutcome = "no_match"
+	PayeeAccountNotFound PayeeOutcome = "account_not_found"
+	PayeeOptedOut        PayeeOutcome = "opted_out"
+)
+
+// Confirmation of Payee reason codes given with every outcome but a match.
+const (
+	ReasonMayBeAMatch                 = "MBAM"
+	ReasonBusinessAccountNameMatched  = "BANM"
+	ReasonPersonalAccountNameMatched  = "PANM"
+	ReasonBusinessAccountCloseMatch   = "BAMM"
+	ReasonPersonalAccountCloseMatch   = "PAMM"
+	ReasonAccountNameNoMatch          = "ANNM"
+	ReasonIncorrectSecondaryReference = "IVCR"
+	ReasonAccountDoesNotExist         = "AC01"
+	ReasonAccountNotSupported         = "ACNS"
+	ReasonAccountOptedOut             = "OPTO"
+)
+
+const (
+	PersonalAccount = "personal"
+	BusinessAccount = "business"
+)
+
+// ConfirmationOfPayeeCriteria is what a payer knows of the account they are about to pay.
+type ConfirmationOfPayeeCriteria struct {
+	BankId                  string
+	AccountNumber           string
+	AccountType             string
+	Name                    string
+	SecondaryIdentification string
+}
+
+type ConfirmationOfPayeeResult struct {
+	Outcome    PayeeOutcome
+	ReasonCode string
+	// ActualName is the name of the account, only given on a close match.
+	ActualName string
+}
+
+// jointHolderSeparator splits the name of a joint account into the names of its holders.
+var jointHolderSeparator = regexp.MustCompile(`(?i)\s+(?:&|and)\s+`)
+
+// ConfirmationOfPayeeQuery checks the name of the account held under the sort code and account
+// number of the criteria. Only the accounts of organisations the user may read are looked at. When
+// more than one of them holds the sort code and account number, the payee cannot be told apart and
+// there is no match.
+func ConfirmationOfPayeeQuery(ctx *context.Context, db *sqlx.DB, criteria ConfirmationOfPayeeCriteria) (*ConfirmationOfPayeeResult, error) {
+	allowedOrganisations, err := security.GetOrganisationsWithPermission(ctx, settings.AccountsRecordType, security.READ)
+	if err != nil {
+		return nil, err
+	}
+	if !allowedOrganisations.IsUnlimited() && len(allowedOrganisations) == 0 {
+		return &ConfirmationOfPayeeResult{Outcome: PayeeAccountNotFound, ReasonCode: ReasonAccountDoesNotExist}, nil
+	}
+
+	whereClause := squirrel.And{
+		squirrel.Eq{"is_deleted": false},
+		squirrel.Eq{storage.RecordAttribute(db.DriverName(), "bank_id"): criteria.BankId},
+		squirrel.Eq{storage.RecordAttribute(db.DriverName(), "account_number"): criteria.AccountNumber},
+	}
+	if !allowedOrganisations.IsUnlimited() {
+		whereClause = append(whereClause, squirrel.Eq{"organisation_id": []uuid.UUID(allowedOrganisations)})
+	}
+
+	sqlStmt, params, err := data.Select("*").From(accountTableName).Where(whereClause).Limit(2).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var records []*internalmodels.AccountRecord
+	if err := db.Select(&records, sqlStmt, params...); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return &ConfirmationOfPayeeResult{Outcome: PayeeAccountNotFound, ReasonCode: ReasonAccountDoesNotExist}, nil
+	}
+	if len(records) > 1 {
+		return &ConfirmationOfPayeeResult{Outcome: PayeeNoMatch, ReasonCode: ReasonAccountNotSupported}, nil
+	}
+
+	result := ConfirmPayee(records[0].Record, criteria)
+	return &result, nil
+}
+
+// ConfirmPayee checks the name and account type the payer gave against the account. A name
+// matching exactly but given for the wrong account type is a close match, as is a name that is
+// merely similar; the payer is then told the actual name of the account.
+func ConfirmPayee(account internalmodels.Account, criteria ConfirmationOfPayeeCriteria) ConfirmationOfPayeeResult {
+	if account.AccountMatchingOptOut != nil && *account.AccountMatchingOptOut {
+		return ConfirmationOfPayeeResult{Outcome: PayeeOptedOut, ReasonCode: ReasonAccountOptedOut}
+	}
+	if criteria.SecondaryIdentification != "" && criteria.SecondaryIdentification != account.SecondaryIdentification {
+		return ConfirmationOfPayeeResult{Outcome: PayeeNoMatch, ReasonCode: ReasonIncorrectSecondaryReference}
+	}
+
+	match := namematching.Match(criteria.Name, payeeNames(account)...)
+	accountType := accountTypeOf(account)
+	typeMatches := accountType == "" || accountType == criteria.AccountType
+
+	switch {
+	case match.Strength == namematching.None:
+		return ConfirmationOfPayeeResult{Outcome: PayeeNoMatch, ReasonCode: ReasonAccountNameNoMatch}
+	case match.Strength == namematching.Exact && typeMatches:
+		return ConfirmationOfPayeeResult{Outcome: PayeeMatch}
+	}
+
+	result := ConfirmationOfPayeeResult{Outcome: PayeeCloseMatch, ReasonCode: ReasonMayBeAMatch, ActualName: account.BankAccountName}
+	if !typeMatches {
+		result.ReasonCode = wrongAccountTypeReason(accountType, match.Strength == namematching.Exact)
+	}
+	return result
+}
+
+// wrongAccountTypeReason tells the payer the account is of the other type than they said.
+func wrongAccountTypeReason(accountType string, nameMatched bool) string {
+	if accountType == BusinessAccount {
+		if nameMatched {
+			return ReasonBusinessAccountNameMatched
+		}
+		return ReasonBusinessAccountCloseMatch
+	}
+	if nameMatched {
+		return ReasonPersonalAccountNameMatched
+	}
+	return ReasonPersonalAccountCloseMatch
+}
+
+// payeeNames are the names an account can be paid under. A payer may give the name of any one of
+// the holders of a joint account, so its names are also split into those of each holder.
+func payeeNames(account internalmodels.Account) []string {
+	names := account.Names()
+	if account.JointAccount == nil || !*account.JointAccount {
+		return names
+	}
+	for _, name := range names {
+		if holders := jointHolderSeparator.Split(name, -1); len(holders) > 1 {
+			names = append(names, holders...)
+		}
+	}
+	return names
+}
+
+// accountTypeOf returns personal or business as the account is classified, or empty when it is not.
+func accountTypeOf(account internalmodels.Account) string {
+	if account.AccountClassification == nil {
+		return ""
+	}
+	return strings.ToLower(*account.AccountClassification)
+}
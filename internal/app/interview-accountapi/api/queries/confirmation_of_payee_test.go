@@ -0,0 +1,84 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmPayee(t *testing.T) {
+	personal, business, yes := "Personal", "Business", true
+	tests := []struct {
+		name     string
+		account  internalmodels.Account
+		criteria ConfirmationOfPayeeCriteria
+		expected ConfirmationOfPayeeResult
+	}{
+		{
+			name:     "match",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder", AccountClassification: &personal},
+			criteria: ConfirmationOfPayeeCriteria{Name: "samantha holder", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeMatch},
+		},
+		{
+			name:     "unclassified account matches either type",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder"},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Samantha Holder", AccountType: BusinessAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeMatch},
+		},
+		{
+			name:     "close match",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder", AccountClassification: &personal},
+			criteria: ConfirmationOfPayeeCriteria{Name: "S Holder", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeCloseMatch, ReasonCode: ReasonMayBeAMatch, ActualName: "Samantha Holder"},
+		},
+		{
+			name:     "business account name matched",
+			account:  internalmodels.Account{BankAccountName: "Holder Ltd", AccountClassification: &business},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Holder Ltd", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeCloseMatch, ReasonCode: ReasonBusinessAccountNameMatched, ActualName: "Holder Ltd"},
+		},
+		{
+			name:     "personal account close match",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder", AccountClassification: &personal},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Samanta Holder", AccountType: BusinessAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeCloseMatch, ReasonCode: ReasonPersonalAccountCloseMatch, ActualName: "Samantha Holder"},
+		},
+		{
+			name:     "joint account holder",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder and Barry White", JointAccount: &yes},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Barry White", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeMatch},
+		},
+		{
+			name:     "holders of an account that is not joint",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder and Barry White"},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Barry White", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeNoMatch, ReasonCode: ReasonAccountNameNoMatch},
+		},
+		{
+			name:     "no match",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder"},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Barry White", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeNoMatch, ReasonCode: ReasonAccountNameNoMatch},
+		},
+		{
+			name:     "incorrect secondary identification",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder", SecondaryIdentification: "A1"},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Samantha Holder", AccountType: PersonalAccount, SecondaryIdentification: "B2"},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeNoMatch, ReasonCode: ReasonIncorrectSecondaryReference},
+		},
+		{
+			name:     "opted out",
+			account:  internalmodels.Account{BankAccountName: "Samantha Holder", AccountMatchingOptOut: &yes},
+			criteria: ConfirmationOfPayeeCriteria{Name: "Samantha Holder", AccountType: PersonalAccount},
+			expected: ConfirmationOfPayeeResult{Outcome: PayeeOptedOut, ReasonCode: ReasonAccountOptedOut},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ConfirmPayee(tt.account, tt.criteria))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+)
+
+const cursorPrefix = "pagination_id:"
+
+// cursor pages through the accounts in the order they were created, starting right after, or
+// right before, the account with the given pagination id. A zero pagination id starts at the
+// first account, or the last one when paging backwards.
+type cursor struct {
+	paginationId int64
+	backwards    bool
+}
+
+// EncodeCursor returns the opaque page[after] or page[before] token pointing at an account.
+func EncodeCursor(paginationId int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(paginationId, 10)))
+}
+
+// DecodeCursor reads a page[after] or page[before] token. The empty token starts at either end of
+// the list.
+func DecodeCursor(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, errors.NewIllegalArgumentError("invalid page cursor")
+	}
+	paginationId, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), cursorPrefix), 10, 64)
+	if err != nil || paginationId <= 0 {
+		return 0, errors.NewIllegalArgumentError("invalid page cursor")
+	}
+	return paginationId, nil
+}
+
+func (c cursor) apply(query squirrel.SelectBuilder) squirrel.SelectBuilder {
+	if c.backwards {
+		if c.paginationId > 0 {
+			query = query.Where(squirrel.Lt{"pagination_id": c.paginationId})
+		}
+		return query.OrderBy("pagination_id DESC")
+	}
+	if c.paginationId > 0 {
+		query = query.Where(squirrel.Gt{"pagination_id": c.paginationId})
+	}
+	return query.OrderBy("pagination_id")
+}
+
+// setCursors puts the page read backwards back in the order the accounts were created, and sets
+// the cursors to the neighbouring pages. The result's HasNext tells whether there were more
+// accounts in the direction the page was read in.
+func (c cursor) setCursors(result *ListAccountsResult) {
+	records := result.DataRecords
+	more := result.HasNext
+	if c.backwards {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	// the page the cursor came from lies on the other side of it
+	first, last := c.paginationId, c.paginationId
+	if len(records) > 0 {
+		first, last = records[0].PaginationId, records[len(records)-1].PaginationId
+	}
+	hasPrev, hasNext := c.paginationId > 0, more
+	if c.backwards {
+		hasPrev, hasNext = more, c.paginationId > 0
+	}
+
+	result.HasNext = hasNext
+	if hasNext {
+		result.NextCursor = EncodeCursor(last)
+	}
+	if hasPrev {
+		result.PrevCursor = EncodeCursor(first)
+	}
+}
@@ -0,0 +1,71 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	paginationId, err := DecodeCursor(EncodeCursor(42))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), paginationId)
+
+	paginationId, err = DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), paginationId)
+
+	for _, token := range []string{"42", "not base64!", EncodeCursor(0), EncodeCursor(-1)} {
+		_, err := DecodeCursor(token)
+		assert.Error(t, err, token)
+	}
+}
+
+func records(paginationIds ...int64) []*internalmodels.AccountRecord {
+	var records []*internalmodels.AccountRecord
+	for _, id := range paginationIds {
+		records = append(records, &internalmodels.AccountRecord{PaginationId: id})
+	}
+	return records
+}
+
+func TestCursor_SetCursors(t *testing.T) {
+	tests := []struct {
+		name       string
+		cursor     cursor
+		read       []*internalmodels.AccountRecord
+		more       bool
+		expected   []int64
+		nextCursor string
+		prevCursor string
+	}{
+		{name: "first page", cursor: cursor{}, read: records(1, 2), more: true,
+			expected: []int64{1, 2}, nextCursor: EncodeCursor(2)},
+		{name: "middle page", cursor: cursor{paginationId: 2}, read: records(3, 4), more: true,
+			expected: []int64{3, 4}, nextCursor: EncodeCursor(4), prevCursor: EncodeCursor(3)},
+		{name: "last page", cursor: cursor{paginationId: 4}, read: records(5),
+			expected: []int64{5}, prevCursor: EncodeCursor(5)},
+		{name: "past the end", cursor: cursor{paginationId: 5},
+			prevCursor: EncodeCursor(5)},
+		{name: "last page backwards", cursor: cursor{backwards: true}, read: records(5, 4), more: true,
+			expected: []int64{4, 5}, prevCursor: EncodeCursor(4)},
+		{name: "first page backwards", cursor: cursor{paginationId: 3, backwards: true}, read: records(2, 1),
+			expected: []int64{1, 2}, nextCursor: EncodeCursor(2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ListAccountsResult{DataRecords: tt.read, HasNext: tt.more}
+			tt.cursor.setCursors(result)
+
+			var paginationIds []int64
+			for _, record := range result.DataRecords {
+				paginationIds = append(paginationIds, record.PaginationId)
+			}
+			assert.Equal(t, tt.expected, paginationIds)
+			assert.Equal(t, tt.nextCursor, result.NextCursor)
+			assert.Equal(t, tt.prevCursor, result.PrevCursor)
+			assert.Equal(t, tt.nextCursor != "", result.HasNext)
+		})
+	}
+}
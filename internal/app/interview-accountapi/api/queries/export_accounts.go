@@ -0,0 +1,125 @@
+package queries
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/form3tech/go-data/data"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportAccountsCriteria selects the accounts of List, all of them rather than a page, and hands
+// each one to Write in turn.
+type ExportAccountsCriteria struct {
+	List  ListAccountsCriteria
+	Write func(record *internalmodels.AccountRecord) error
+}
+
+type ExportAccountsResult struct {
+	Exported int
+}
+
+// ExportAccountsQuery reads the accounts off the database cursor one at a time, so that however
+// many there are, only one is held in memory. The query stops at the first error of Write, or once
+// the request of ctx is done. With a single connection to the database, as to an in-memory SQLite
+// one, holding it until the client has read the last account would hold up every other request, so
+// the accounts are then first copied to a temporary file and written from there.
+func ExportAccountsQuery(ctx *context.Context, db *sqlx.DB, criteria ExportAccountsCriteria) (*ExportAccountsResult, error) {
+	result := ExportAccountsResult{}
+
+	readable, err := criteria.List.restrictToReadableOrganisations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !readable {
+		return &result, nil
+	}
+
+	sqlStmt, params, err := data.Select("*").
+		From(accountTableName).
+		Where(criteria.List.whereClause(db.DriverName())).
+		OrderBy(orderBy(criteria.List.sort, db.DriverName())...).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if db.Stats().MaxOpenConnections == 1 {
+		result.Exported, err = exportBuffered(*ctx, db, sqlStmt, params, criteria.Write)
+	} else {
+		result.Exported, err = readAccounts(*ctx, db, sqlStmt, params, criteria.Write)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// readAccounts hands each account the query selects to write, and returns how many it wrote.
+func readAccounts(ctx context.Context, db *sqlx.DB, sqlStmt string, params []interface{}, write func(record *internalmodels.AccountRecord) error) (int, error) {
+	rows, err := db.QueryxContext(ctx, sqlStmt, params...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	read := 0
+	for rows.Next() {
+		record := &internalmodels.AccountRecord{}
+		if err := rows.StructScan(record); err != nil {
+			return read, err
+		}
+		if err := write(record); err != nil {
+			return read, err
+		}
+		read++
+	}
+	return read, rows.Err()
+}
+
+// exportBuffered reads the accounts the query selects into a temporary file, releasing the
+// connection before any of them is handed to write.
+func exportBuffered(ctx context.Context, db *sqlx.DB, sqlStmt string, params []interface{}, write func(record *internalmodels.AccountRecord) error) (int, error) {
+	file, err := ioutil.TempFile("", "accounts-export-")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	buffer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(buffer)
+	read, err := readAccounts(ctx, db, sqlStmt, params, func(record *internalmodels.AccountRecord) error {
+		return encoder.Encode(record)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := buffer.Flush(); err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for exported := 0; exported < read; exported++ {
+		if err := ctx.Err(); err != nil {
+			return exported, err
+		}
+		record := &internalmodels.AccountRecord{}
+		if err := decoder.Decode(record); err != nil {
+			return exported, err
+		}
+		if err := write(record); err != nil {
+			return exported, err
+		}
+	}
+	return read, nil
+}
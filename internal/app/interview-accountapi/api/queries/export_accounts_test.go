@@ -0,0 +1,97 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/form3tech/go-security/security"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+)
+
+// singleConnectionDatabase is an in-memory database, which has a single connection, holding
+// the given number of accounts of an organisation.
+func singleConnectionDatabase(t *testing.T, accounts int) *sqlx.DB {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := migrate.Exec(db.DB, "sqlite3", &migrate.FileMigrationSource{Dir: "../migrations/sqlite3"}, migrate.Up); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db.MustExec(`DELETE FROM "Account"`)
+
+	accountStorage := storage.NewAccountStorage(db)
+	organisationID := uuid.New()
+	for n := 0; n < accounts; n++ {
+		version := int64(0)
+		record := &internalmodels.AccountRecord{
+			ID:             uuid.New(),
+			OrganisationID: organisationID,
+			Version:        &version,
+			CreatedOn:      time.Now(),
+			ModifiedOn:     time.Now(),
+			Record:         internalmodels.Account{BankID: "400300", AccountNumber: fmt.Sprintf("4142681%d", n)},
+		}
+		if !assert.NoError(t, accountStorage.Create(record)) {
+			t.FailNow()
+		}
+	}
+	return db
+}
+
+func TestExportAccountsQuery_ReleasesTheOnlyConnectionBeforeWriting(t *testing.T) {
+	db := singleConnectionDatabase(t, 3)
+	ctx := security.ApplicationContext(context.Background())
+	var written []string
+	criteria := ExportAccountsCriteria{
+		List: NewListAccountsCriteriaBuilder().Build(),
+		Write: func(record *internalmodels.AccountRecord) error {
+			queryCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			var count int
+			if err := db.GetContext(queryCtx, &count, `SELECT COUNT(*) FROM "Account"`); err != nil {
+				return err
+			}
+			written = append(written, record.Record.AccountNumber)
+			return nil
+		},
+	}
+
+	result, err := ExportAccountsQuery(ctx, db, criteria)
+
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 3, result.Exported)
+	assert.ElementsMatch(t, []string{"41426810", "41426811", "41426812"}, written)
+}
+
+func TestExportAccountsQuery_StopsOnceTheRequestIsDone(t *testing.T) {
+	db := singleConnectionDatabase(t, 3)
+	requestCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx := security.ApplicationContext(requestCtx)
+	written := 0
+	criteria := ExportAccountsCriteria{
+		List: NewListAccountsCriteriaBuilder().Build(),
+		Write: func(record *internalmodels.AccountRecord) error {
+			written++
+			cancel()
+			return nil
+		},
+	}
+
+	_, err := ExportAccountsQuery(ctx, db, criteria)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, written)
+}
@@ -17,7 +17,8 @@ type GetAccountByIdResult struct {
 }
 
 type GetAccountByIdCriteria struct {
-	AccountId uuid.UUID
+	AccountId      uuid.UUID
+	IncludeDeleted bool
 }
 
 func GetAccountByIdCriteriaBuilder(id uuid.UUID) GetAccountByIdCriteria {
@@ -26,13 +27,23 @@ func GetAccountByIdCriteriaBuilder(id uuid.UUID) GetAccountByIdCriteria {
 	}
 }
 
+// WithIncludeDeleted makes the query also find the account once it has been deleted.
+func (c GetAccountByIdCriteria) WithIncludeDeleted(includeDeleted bool) GetAccountByIdCriteria {
+	c.IncludeDeleted = includeDeleted
+	return c
+}
+
 func GetAccountByIdQuery(db *sqlx.DB, q GetAccountByIdCriteria) (*GetAccountByIdResult, error) {
 	dataRecord := &internalmodels.AccountRecord{}
 
+	whereClause := squirrel.Eq{"id": q.AccountId}
+	if !q.IncludeDeleted {
+		whereClause["is_deleted"] = false
+	}
 	sqlStmt, params, err := data.
 		Select("*").
 		From(`"Account"`).
-		Where(squirrel.Eq{"id": q.AccountId}).
+		Where(whereClause).
 		ToSql()
 	if err != nil {
 		return nil, err
@@ -2,18 +2,65 @@ package queries
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/form3tech/go-data/data"
 	"github.com/form3tech/go-form3-web/web"
+	"github.com/form3tech/go-security/security"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
+// FilterableAttributes are the attributes of the account record that the list can be filtered on.
+var FilterableAttributes = []string{
+	"country",
+	"base_currency",
+	"bank_id",
+	"bank_id_code",
+	"account_number",
+	"iban",
+	"bic",
+	"customer_id",
+	"account_classification",
+}
+
+const accountTableName = `"Account"`
+
 type ListAccountsCriteria struct {
 	pageCriteria            web.PageCriteria
 	filteredOrganisationIds []uuid.UUID
+	filteredLocked          *bool
+	filteredAttributes      map[string][]string
+	createdOn               timeRange
+	modifiedOn              timeRange
+	sort                    []SortField
+	cursor                  *cursor
+	skipCount               bool
+	includeDeleted          bool
+}
+
+// timeRange selects the times at or after from and before to. Either end may be left open.
+type timeRange struct {
+	from *time.Time
+	to   *time.Time
+}
+
+func (r timeRange) where(column string) squirrel.And {
+	clause := squirrel.And{}
+	if r.from != nil {
+		clause = append(clause, squirrel.GtOrEq{column: r.from.UTC()})
+	}
+	if r.to != nil {
+		clause = append(clause, squirrel.Lt{column: r.to.UTC()})
+	}
+	return clause
 }
 
 type ListAccountCriteriaBuilder struct {
@@ -31,59 +78,178 @@ func (b *ListAccountCriteriaBuilder) WithFilterByOrganisationId(organisationIds
 	b.data.filteredOrganisationIds = organisationIds
 	return b
 }
+func (b *ListAccountCriteriaBuilder) WithFilterByLocked(locked *bool) *ListAccountCriteriaBuilder {
+	b.data.filteredLocked = locked
+	return b
+}
+// WithFilterByAttribute only lists accounts whose attribute, one of FilterableAttributes, has one
+// of the given values.
+func (b *ListAccountCriteriaBuilder) WithFilterByAttribute(attribute string, values []string) *ListAccountCriteriaBuilder {
+	if len(values) == 0 {
+		return b
+	}
+	if b.data.filteredAttributes == nil {
+		b.data.filteredAttributes = map[string][]string{}
+	}
+	b.data.filteredAttributes[attribute] = values
+	return b
+}
+func (b *ListAccountCriteriaBuilder) WithCreatedOnRange(from *time.Time, to *time.Time) *ListAccountCriteriaBuilder {
+	b.data.createdOn = timeRange{from: from, to: to}
+	return b
+}
+func (b *ListAccountCriteriaBuilder) WithModifiedOnRange(from *time.Time, to *time.Time) *ListAccountCriteriaBuilder {
+	b.data.modifiedOn = timeRange{from: from, to: to}
+	return b
+}
+func (b *ListAccountCriteriaBuilder) WithSort(sort []SortField) *ListAccountCriteriaBuilder {
+	b.data.sort = sort
+	return b
+}
+// WithPageAfter reads the page of accounts created after the one with the given pagination id,
+// or the first page when it is zero, instead of the page given by the page criteria.
+func (b *ListAccountCriteriaBuilder) WithPageAfter(paginationId int64) *ListAccountCriteriaBuilder {
+	b.data.cursor = &cursor{paginationId: paginationId}
+	return b
+}
+
+// WithPageBefore reads the page of accounts created before the one with the given pagination id,
+// or the last page when it is zero, instead of the page given by the page criteria.
+func (b *ListAccountCriteriaBuilder) WithPageBefore(paginationId int64) *ListAccountCriteriaBuilder {
+	b.data.cursor = &cursor{paginationId: paginationId, backwards: true}
+	return b
+}
+
+// WithoutCount skips counting the matching accounts, so that the result has no TotalRecords.
+func (b *ListAccountCriteriaBuilder) WithoutCount() *ListAccountCriteriaBuilder {
+	b.data.skipCount = true
+	return b
+}
+func (b *ListAccountCriteriaBuilder) WithIncludeDeleted(includeDeleted bool) *ListAccountCriteriaBuilder {
+	b.data.includeDeleted = includeDeleted
+	return b
+}
 func (b *ListAccountCriteriaBuilder) Build() ListAccountsCriteria {
 	return b.data
 }
 
-func (c ListAccountsCriteria) buildQuery(builder data.PagedBuilder) data.PagedBuilder {
+func (c ListAccountsCriteria) whereClause(driver string) squirrel.And {
 	whereClause := squirrel.And{}
 
+	if !c.includeDeleted {
+		whereClause = append(whereClause, squirrel.Eq{"is_deleted": false})
+	}
+
 	if len(c.filteredOrganisationIds) > 0 {
 		whereClause = append(whereClause, squirrel.Eq{"organisation_id": c.filteredOrganisationIds})
 	}
-	if len(whereClause) > 0 {
-		return builder.Where(whereClause)
+	if c.filteredLocked != nil {
+		whereClause = append(whereClause, squirrel.Eq{"is_locked": *c.filteredLocked})
+	}
+	for _, attribute := range FilterableAttributes {
+		if values, ok := c.filteredAttributes[attribute]; ok {
+			whereClause = append(whereClause, squirrel.Eq{storage.RecordAttribute(driver, attribute): values})
+		}
+	}
+	whereClause = append(whereClause, c.createdOn.where("created_on")...)
+	whereClause = append(whereClause, c.modifiedOn.where("modified_on")...)
+	return whereClause
+}
+
+// restrictToReadableOrganisations narrows the organisations of the criteria down to those the
+// user may read. It returns false when the user may not read any of the organisations asked for.
+func (c *ListAccountsCriteria) restrictToReadableOrganisations(ctx *context.Context) (bool, error) {
+	allowedOrganisations, err := security.GetOrganisationsWithPermission(ctx, settings.AccountsRecordType, security.READ)
+	if err != nil {
+		return false, err
 	}
-	return builder
+	c.filteredOrganisationIds = allowedOrganisations.IntersectFilter(c.filteredOrganisationIds)
+	return allowedOrganisations.IsUnlimited() || len(c.filteredOrganisationIds) > 0, nil
 }
 
 type ListAccountsResult struct {
+	// PageResults has no TotalRecords when the count was skipped, and no CurrentPage when paging
+	// with a cursor.
 	PageResults web.PageResults
 	DataRecords []*internalmodels.AccountRecord
+	// Counted tells whether PageResults.TotalRecords was filled in.
+	Counted bool
+	// HasNext tells whether there are accounts after this page. It is always set when paging by
+	// page number without counting, or with a cursor.
+	HasNext bool
+	// NextCursor and PrevCursor, when paging with a cursor, point at the pages after and before
+	// this one. They are empty when there is no such page.
+	NextCursor string
+	PrevCursor string
 }
 
 func ListAccountsQuery(ctx *context.Context, db *sqlx.DB, criteria ListAccountsCriteria) (*ListAccountsResult, error) {
 	result := ListAccountsResult{}
 
-	query := data.
-		Paged("*").
-		From(`"Account"`)
-	query = criteria.buildQuery(query)
-
-	countSqlStmt, params, err := query.ToCount().ToSql()
+	readable, err := criteria.restrictToReadableOrganisations(ctx)
 	if err != nil {
 		return nil, err
 	}
-	rowCount := 0
-	err = db.Get(&rowCount, countSqlStmt, params...)
-	if err != nil {
-		return nil, err
+	if !readable {
+		result.PageResults.PageSize = criteria.pageCriteria.PageSize
+		result.Counted = true
+		return &result, nil
+	}
+
+	whereClause := criteria.whereClause(db.DriverName())
+	pageSize := criteria.pageCriteria.PageSize
+	result.PageResults.PageSize = pageSize
+
+	// the last page number can only be worked out from the count
+	if !criteria.skipCount || (criteria.cursor == nil && strings.EqualFold(criteria.pageCriteria.PageNumber, "last")) {
+		countSqlStmt, params, err := data.Select("COUNT(*)").From(accountTableName).Where(whereClause).ToSql()
+		if err != nil {
+			return nil, err
+		}
+		rowCount := 0
+		if err := db.Get(&rowCount, countSqlStmt, params...); err != nil {
+			return nil, err
+		}
+		result.PageResults.TotalRecords = rowCount
+		result.Counted = true
 	}
-	result.PageResults.TotalRecords = rowCount
-	result.PageResults.CurrentPage = criteria.pageCriteria.GetPageNumber(rowCount)
-	result.PageResults.PageSize = criteria.pageCriteria.PageSize
 
-	sqlStmt, params, err := query.ToSelect(result.PageResults.CurrentPage, result.PageResults.PageSize).
-		OrderBy("pagination_id").
-		ToSql()
+	// one account more than the page size is read to find out whether there is a next page
+	query := data.Select("*").From(accountTableName).Where(whereClause).Limit(uint64(pageSize + 1))
+	if criteria.cursor == nil {
+		result.PageResults.CurrentPage = criteria.pageCriteria.GetPageNumber(result.PageResults.TotalRecords)
+		if result.Counted {
+			lastPage := 0
+			if result.PageResults.TotalRecords > 0 {
+				lastPage = (result.PageResults.TotalRecords - 1) / pageSize
+			}
+			if result.PageResults.CurrentPage > lastPage {
+				return nil, errors.NewIllegalArgumentError(fmt.Sprintf("page[number] %d is beyond the last page %d", result.PageResults.CurrentPage, lastPage))
+			}
+		}
+		query = query.
+			OrderBy(orderBy(criteria.sort, db.DriverName())...).
+			Offset(uint64(result.PageResults.CurrentPage * pageSize))
+	} else {
+		query = criteria.cursor.apply(query)
+	}
+
+	sqlStmt, params, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
-
 	err = db.Select(&result.DataRecords, sqlStmt, params...)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(result.DataRecords) > pageSize {
+		result.DataRecords = result.DataRecords[:pageSize]
+		result.HasNext = true
+	}
+	if criteria.cursor != nil {
+		criteria.cursor.setCursors(&result)
+	}
+
 	return &result, nil
 }
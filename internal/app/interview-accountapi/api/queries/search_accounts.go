@@ -0,0 +1,102 @@
+package queries
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/form3tech/go-data/data"
+	"github.com/form3tech/go-security/security"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/namematching"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// SearchAccountsCriteria looks for the accounts named like Name. The accounts can be narrowed
+// down by organisation and by the attributes a payer knows, such as the sort code and account number.
+type SearchAccountsCriteria struct {
+	Name                    string
+	OrganisationIds         []uuid.UUID
+	BankId                  string
+	AccountNumber           string
+	SecondaryIdentification string
+	Limit                   int
+}
+
+type AccountMatch struct {
+	DataRecord *internalmodels.AccountRecord
+	Match      namematching.Result
+}
+
+type SearchAccountsResult struct {
+	Matches []AccountMatch
+}
+
+// SearchAccountsQuery scores the names of the accounts that may be read and match the criteria,
+// best match first. Accounts opted out of account matching are never matched. Accounts that do not
+// match the name are left out, unless the search is for a given account number, in which case they
+// are returned with a match strength of none.
+//
+// Unless the search is for a given account number, only the accounts sharing a name key with the
+// name are scored, at most settings.MaxSearchCandidates of them. A name misspelt at the start of
+// every word is then not matched.
+func SearchAccountsQuery(ctx *context.Context, db *sqlx.DB, criteria SearchAccountsCriteria) (*SearchAccountsResult, error) {
+	result := SearchAccountsResult{}
+
+	allowedOrganisations, err := security.GetOrganisationsWithPermission(ctx, settings.AccountsRecordType, security.READ)
+	if err != nil {
+		return nil, err
+	}
+	organisationIds := allowedOrganisations.IntersectFilter(criteria.OrganisationIds)
+	if !allowedOrganisations.IsUnlimited() && len(organisationIds) == 0 {
+		return &result, nil
+	}
+
+	// accounts opted out are left out before the candidates are capped, so as not to take up the cap
+	whereClause := squirrel.And{squirrel.Eq{"is_deleted": false}, storage.MatchingAllowed(db.DriverName())}
+	if len(organisationIds) > 0 {
+		whereClause = append(whereClause, squirrel.Eq{"organisation_id": organisationIds})
+	}
+	for attribute, value := range map[string]string{
+		"bank_id":                  criteria.BankId,
+		"account_number":           criteria.AccountNumber,
+		"secondary_identification": criteria.SecondaryIdentification,
+	} {
+		if value != "" {
+			whereClause = append(whereClause, squirrel.Eq{storage.RecordAttribute(db.DriverName(), attribute): value})
+		}
+	}
+
+	query := data.Select("*").From(accountTableName)
+	if criteria.AccountNumber == "" {
+		whereClause = append(whereClause, storage.MatchesNameKeys(namematching.Keys(criteria.Name)))
+		query = query.Limit(uint64(settings.MaxSearchCandidates))
+	}
+	sqlStmt, params, err := query.Where(whereClause).OrderBy("pagination_id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var records []*internalmodels.AccountRecord
+	if err := db.Select(&records, sqlStmt, params...); err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		match := namematching.Match(criteria.Name, record.Record.Names()...)
+		if match.Strength == namematching.None && criteria.AccountNumber == "" {
+			continue
+		}
+		result.Matches = append(result.Matches, AccountMatch{DataRecord: record, Match: match})
+	}
+
+	sort.SliceStable(result.Matches, func(i, j int) bool {
+		return result.Matches[i].Match.Better(result.Matches[j].Match)
+	})
+	if criteria.Limit > 0 && len(result.Matches) > criteria.Limit {
+		result.Matches = result.Matches[:criteria.Limit]
+	}
+	return &result, nil
+}
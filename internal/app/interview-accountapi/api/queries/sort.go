@@ -0,0 +1,79 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+)
+
+// sortableColumns are the columns of the Account table the list can be sorted on.
+var sortableColumns = map[string]bool{
+	"created_on":  true,
+	"modified_on": true,
+}
+
+// SortableAttributes are the attributes of the account record the list can be sorted on, on top
+// of created_on and modified_on.
+var SortableAttributes = append([]string{"bank_account_name"}, FilterableAttributes...)
+
+// SortField orders the list on one field, ascending unless Descending is set.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort reads a sort parameter such as "-created_on,bank_account_name": a comma separated list
+// of fields, each of which is prefixed by "-" to sort on it in descending order.
+func ParseSort(value string) ([]SortField, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var fields []SortField
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		field := SortField{Field: part}
+		if strings.HasPrefix(part, "-") {
+			field = SortField{Field: strings.TrimPrefix(part, "-"), Descending: true}
+		}
+		if !isSortable(field.Field) {
+			return nil, errors.NewIllegalArgumentError(fmt.Sprintf("cannot sort on %q", field.Field))
+		}
+		if seen[field.Field] {
+			return nil, errors.NewIllegalArgumentError(fmt.Sprintf("cannot sort on %q more than once", field.Field))
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func isSortable(field string) bool {
+	if sortableColumns[field] {
+		return true
+	}
+	for _, attribute := range SortableAttributes {
+		if attribute == field {
+			return true
+		}
+	}
+	return false
+}
+
+// orderBy returns the ORDER BY expressions for the sort fields, ending with pagination_id so that
+// accounts sorting equal keep the same order from one page to the next.
+func orderBy(fields []SortField, driver string) []string {
+	var expressions []string
+	for _, field := range fields {
+		expression := field.Field
+		if !sortableColumns[field.Field] {
+			expression = storage.RecordAttribute(driver, field.Field)
+		}
+		if field.Descending {
+			expression += " DESC"
+		}
+		expressions = append(expressions, expression)
+	}
+	return append(expressions, "pagination_id")
+}
@@ -0,0 +1,47 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []SortField
+		invalid  bool
+	}{
+		{value: "", expected: nil},
+		{value: "created_on", expected: []SortField{{Field: "created_on"}}},
+		{value: "-created_on,bank_account_name", expected: []SortField{{Field: "created_on", Descending: true}, {Field: "bank_account_name"}}},
+		{value: "-account_number", expected: []SortField{{Field: "account_number", Descending: true}}},
+		{value: "record", invalid: true},
+		{value: "created_on,", invalid: true},
+		{value: "--created_on", invalid: true},
+		{value: "created_on,-created_on", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			fields, err := ParseSort(tt.value)
+			if tt.invalid {
+				assert.IsType(t, &errors.IllegalArgumentError{}, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	fields := []SortField{{Field: "created_on", Descending: true}, {Field: "bank_account_name"}}
+
+	assert.Equal(t,
+		[]string{"created_on DESC", "json_extract(CAST(record AS TEXT), '$.bank_account_name')", "pagination_id"},
+		orderBy(fields, "sqlite3"))
+	assert.Equal(t,
+		[]string{"created_on DESC", "record->>'bank_account_name'", "pagination_id"},
+		orderBy(fields, "postgres"))
+}
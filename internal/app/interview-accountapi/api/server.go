@@ -15,26 +15,39 @@ import (
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/processors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/queries"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/log"
 	"github.com/giantswarm/retry-go"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
 	_ "github.com/mattes/migrate/source/file"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rubenv/sql-migrate"
 	"github.com/spf13/viper"
 )
 
+const requestIdHeader = "X-Request-Id"
+
+const (
+	sqliteDriver   = "sqlite3"
+	postgresDriver = "postgres"
+)
+
 func Configure() {
 	viper.AutomaticEnv()
 	viper.SetDefault("MessageVisibilityTimeout", 60)
 
-	db := connectToDatabase()
+	db := connectToDatabase(settings.DatabaseDriver, databaseDSN(settings.DatabaseDriver, settings.DatabaseDSN))
 
-	migrateDatabase(db.DB)
+	migrateDatabase(db.DB, settings.DatabaseDriver)
+	fillNameKeys(db)
 
 	settings.ApplicationClientId, settings.ApplicationClientSecret = getApplicationCredentials()
 
+	configureAuthentication()
+
 	executors.Configure(db)
 
 	processors.Configure()
@@ -45,12 +58,36 @@ func Configure() {
 	setupRoutes()
 }
 
-func connectToDatabase() *sqlx.DB {
+// databaseDSN returns the DB_DSN setting or, when it is not set, an in-memory SQLite database or
+// a PostgreSQL connection string assembled from the DB_HOST, DB_PORT, DB_USER, DB_PASSWORD,
+// DB_NAME and DB_SSL_MODE settings.
+func databaseDSN(driver string, dsn string) string {
+	if dsn != "" {
+		return dsn
+	}
+	if driver == postgresDriver {
+		return newConnectionString(
+			getOrDefaultString("DB_HOST", "localhost"),
+			getOrDefaultString("DB_USER", "postgres"),
+			getOrDefaultString("DB_PASSWORD", ""),
+			getOrDefaultString("DB_NAME", settings.ServiceName),
+			getOrDefaultInt("DB_PORT", 5432),
+			getOrDefaultString("DB_SSL_MODE", "disable"),
+		).String()
+	}
+	return ":memory:"
+}
+
+func connectToDatabase(driver string, dsn string) *sqlx.DB {
+	if driver != sqliteDriver && driver != postgresDriver {
+		panic(fmt.Sprintf("unsupported database driver %q, expected %q or %q", driver, sqliteDriver, postgresDriver))
+	}
+
 	var db *sqlx.DB
 	var err error
 
 	_ = retry.Do(func() error {
-		db, err = sqlx.Connect("sqlite3", ":memory:")
+		db, err = sqlx.Connect(driver, dsn)
 		if err != nil {
 			return err
 		}
@@ -63,21 +100,34 @@ func connectToDatabase() *sqlx.DB {
 		panic(err)
 	}
 
+	if driver == sqliteDriver && strings.Contains(dsn, ":memory:") {
+		// every connection to :memory: opens a database of its own
+		db.SetMaxOpenConns(1)
+	}
+
 	return db
 }
 
-func migrateDatabase(db *sql.DB) {
-	dir, err := os.Getwd()
+// fillNameKeys stores the name keys of the accounts created before searches looked them up.
+func fillNameKeys(db *sqlx.DB) {
+	n, err := storage.NewAccountStorage(db).FillNameKeys(500)
 	if err != nil {
-		log.Fatal(err)
+		panic(fmt.Sprintf("could not fill in account name keys, error: %v", err))
 	}
+	if n > 0 {
+		log.Infof("filled in the name keys of %d accounts", n)
+	}
+}
 
-	path := "internal/app/interview-accountapi/api/migrations/"
-	if strings.HasSuffix(dir,"internal/app/interview-accountapi") {
-		path = "api/migrations/"
+// migrateDatabase applies the migrations written for the given driver, which live in a directory
+// of the same name under api/migrations.
+func migrateDatabase(db *sql.DB, driver string) {
+	path, err := migrationsDir(driver)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	n, err := migrate.Exec(db, "postgres", &migrate.FileMigrationSource{Dir: path}, migrate.Up)
+	n, err := migrate.Exec(db, driver, &migrate.FileMigrationSource{Dir: path}, migrate.Up)
 	if err != nil {
 		panic(fmt.Sprintf("could not migrate database, error: %v", err))
 	}
@@ -85,6 +135,21 @@ func migrateDatabase(db *sql.DB) {
 	log.Infof("applied %d database migrations!\n", n)
 }
 
+func migrationsDir(driver string) (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	path := "internal/app/interview-accountapi/api/migrations/"
+	if strings.HasSuffix(dir, "internal/app/interview-accountapi") {
+		path = "api/migrations/"
+	} else if strings.HasSuffix(dir, "internal/app/interview-accountapi/api") {
+		path = "migrations/"
+	}
+	return path + driver, nil
+}
+
 func getApplicationCredentials() (string, string) {
 	return viper.GetString(settings.ServiceName + "-credentials-client-id"), viper.GetString(settings.ServiceName + "-credentials-client-secret")
 }
@@ -92,27 +157,48 @@ func getApplicationCredentials() (string, string) {
 func setupRoutes() {
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(setupRequestId())
 	router.Use(setupGinLogger())
 
-	http.HandleFunc("/", router.ServeHTTP)
+	http.HandleFunc("/", withResponseController(router.ServeHTTP))
 
-	router.NoRoute(func(c *gin.Context) {
-		c.JSON(404, gin.H{"code": "PAGE_NOT_FOUND", "message": "Page not found"})
-	})
+	router.NoRoute(handleNoRoute)
 
 	v1 := router.Group("/v1")
 	v1.GET("/health", HandleGetHealth)
 
 	accounts := v1.Group("/organisation/accounts").Use(gin.Logger())
 	{
-		accounts.GET("/:id", WithUserContext(HandleGetAccountById))
+		accounts.GET("/:id", withStaticRoute("search", WithUserContext(HandleSearchAccounts),
+			withStaticRoute("export", WithUserContext(HandleExportAccounts), WithUserContext(HandleGetAccountById))))
+		accounts.PATCH("/:id", WithUserContext(HandleUpdateAccount))
 		accounts.DELETE("/:id", WithUserContext(HandleDeleteAccount))
+		accounts.POST("/:id", withStaticRoute("confirmation-of-payee", WithUserContext(HandleConfirmationOfPayee),
+			withStaticRoute("bulk", WithUserContext(HandleCreateAccounts), handleNoRoute)))
+		accounts.POST("/:id/lock", WithUserContext(HandleLockAccount))
+		accounts.POST("/:id/unlock", WithUserContext(HandleUnlockAccount))
 		accounts.GET("", WithUserContext(HandleListAccounts))
 		accounts.POST("", WithUserContext(HandleCreateAccount))
 	}
 
 }
 
+func handleNoRoute(c *gin.Context) {
+	c.JSON(404, gin.H{"code": "PAGE_NOT_FOUND", "message": "Page not found"})
+}
+
+// withStaticRoute serves the static path segment with its own handler. Gin cannot register a static
+// route next to the :id wildcard, so the wildcard's handler hands it over.
+func withStaticRoute(segment string, static gin.HandlerFunc, wildcard gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param("id") == segment {
+			static(c)
+			return
+		}
+		wildcard(c)
+	}
+}
+
 // setupGinLogger adds a Gin middleware that logs on endpoints other than the health check
 func setupGinLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -122,6 +208,20 @@ func setupGinLogger() gin.HandlerFunc {
 	}
 }
 
+// setupRequestId adds a Gin middleware that echoes the caller's request id, or generates one,
+// and exposes it as the correlation id used by the request loggers
+func setupRequestId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(requestIdHeader)
+		if requestId == "" {
+			requestId = uuid.New().String()
+		}
+		c.Set("correlation-id", requestId)
+		c.Header(requestIdHeader, requestId)
+		c.Next()
+	}
+}
+
 func StartServer(ch <-chan bool, startedSignal chan bool) {
 	port := settings.ServerPort
 	address := fmt.Sprintf(":%d", port)
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseDSN(t *testing.T) {
+	assert.Equal(t, "accounts.db", databaseDSN(sqliteDriver, "accounts.db"))
+	assert.Equal(t, ":memory:", databaseDSN(sqliteDriver, ""))
+	assert.Equal(t, "host=localhost port=5432 user=postgres password= dbname=interview_accountapi sslmode=disable",
+		databaseDSN(postgresDriver, ""))
+}
+
+func TestConnectToDatabase_UnsupportedDriver(t *testing.T) {
+	assert.Panics(t, func() { connectToDatabase("mysql", "") })
+}
+
+func TestFileBackedSqliteKeepsAccountsAcrossRestarts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accountapi")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	dsn := filepath.Join(dir, "accounts.db")
+
+	db := connectToDatabase(sqliteDriver, dsn)
+	migrateDatabase(db.DB, sqliteDriver)
+
+	version := int64(0)
+	country := "GB"
+	record := &internalmodels.AccountRecord{
+		ID:             uuid.New(),
+		OrganisationID: uuid.New(),
+		Version:        &version,
+		CreatedOn:      time.Now(),
+		ModifiedOn:     time.Now(),
+		Record:         internalmodels.Account{Country: &country},
+	}
+	assert.NoError(t, storage.NewAccountStorage(db).Create(record))
+	assert.NoError(t, db.Close())
+
+	db = connectToDatabase(sqliteDriver, dsn)
+	defer db.Close()
+	migrateDatabase(db.DB, sqliteDriver)
+
+	stored, err := storage.NewAccountStorage(db).Get(record.ID)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, record.OrganisationID, stored.OrganisationID)
+	assert.Equal(t, "GB", *stored.Record.Country)
+}
@@ -1,7 +1,9 @@
 package settings
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"sync"
 )
 
@@ -9,15 +11,41 @@ const (
 	ApiName     = "interview-accountapi"
 	UserID      = "9ef0183d-600f-415b-975e-2b722afc74f2"
 	ServiceName = "interview_accountapi"
+
+	AccountsRecordType      = "accounts"
+	AccountsAdminRecordType = "accounts_admin"
 )
 
 var (
 	ServerPort              = 8080
+	MaxPageSize             = 1000
 	ApplicationClientId     string
 	ApplicationClientSecret string
 	StackName               string
 	LogFormat               string
 	LogLevel                string
+	JwtPublicKey            string
+	EventSink               string
+	EventSinkFile           string
+	EventSinkWebhookURL     string
+	DatabaseDriver          string
+	DatabaseDSN             string
+	// AuthDisabled lets the server run without JwtPublicKey, serving every request with the
+	// rights of the application. It has no effect when a key is set.
+	AuthDisabled bool
+	// AccountNumberFirstSequence is the sequence number account numbers are generated from under a
+	// bank id the organisation has no sequence for yet.
+	AccountNumberFirstSequence = 1
+	// MaxBulkCreateSize is the most accounts a bulk create may carry, which are inserted
+	// BulkCreateBatchSize to a transaction.
+	MaxBulkCreateSize   = 10000
+	BulkCreateBatchSize = 500
+	// MaxSearchCandidates is the most accounts a search by name reads and scores, out of those
+	// sharing a name key with the name searched for.
+	MaxSearchCandidates = 1000
+	// ExportWriteTimeoutSeconds is how long a client downloading an export may take to read each
+	// part of it, before the export is cut short.
+	ExportWriteTimeoutSeconds = 30
 )
 
 var settingsOnce sync.Once
@@ -27,6 +55,37 @@ func Configure() {
 		StackName = GetStringOrDefault("STACK_NAME", "local")
 		LogFormat = os.Getenv("LOG_FORMAT")
 		LogLevel = os.Getenv("LOG_LEVEL")
+		JwtPublicKey = os.Getenv("JWT_PUBLIC_KEY")
+		AuthDisabled = GetBoolOrDefault("AUTH_DISABLED", false)
+		EventSink = GetStringOrDefault("EVENT_SINK", "sns")
+		EventSinkFile = GetStringOrDefault("EVENT_SINK_FILE", "events.jsonl")
+		EventSinkWebhookURL = os.Getenv("EVENT_SINK_WEBHOOK_URL")
+		DatabaseDriver = GetStringOrDefault("DB_DRIVER", "sqlite3")
+		DatabaseDSN = os.Getenv("DB_DSN")
+		MaxPageSize = GetIntOrDefault("MAX_PAGE_SIZE", MaxPageSize)
+		if MaxPageSize < 1 {
+			panic(fmt.Sprintf("MAX_PAGE_SIZE must be at least 1, got %d", MaxPageSize))
+		}
+		MaxBulkCreateSize = GetIntOrDefault("MAX_BULK_CREATE_SIZE", MaxBulkCreateSize)
+		if MaxBulkCreateSize < 1 {
+			panic(fmt.Sprintf("MAX_BULK_CREATE_SIZE must be at least 1, got %d", MaxBulkCreateSize))
+		}
+		BulkCreateBatchSize = GetIntOrDefault("BULK_CREATE_BATCH_SIZE", BulkCreateBatchSize)
+		if BulkCreateBatchSize < 1 {
+			panic(fmt.Sprintf("BULK_CREATE_BATCH_SIZE must be at least 1, got %d", BulkCreateBatchSize))
+		}
+		MaxSearchCandidates = GetIntOrDefault("MAX_SEARCH_CANDIDATES", MaxSearchCandidates)
+		if MaxSearchCandidates < 1 {
+			panic(fmt.Sprintf("MAX_SEARCH_CANDIDATES must be at least 1, got %d", MaxSearchCandidates))
+		}
+		ExportWriteTimeoutSeconds = GetIntOrDefault("EXPORT_WRITE_TIMEOUT_SECONDS", ExportWriteTimeoutSeconds)
+		if ExportWriteTimeoutSeconds < 1 {
+			panic(fmt.Sprintf("EXPORT_WRITE_TIMEOUT_SECONDS must be at least 1, got %d", ExportWriteTimeoutSeconds))
+		}
+		AccountNumberFirstSequence = GetIntOrDefault("ACCOUNT_NUMBER_FIRST_SEQUENCE", AccountNumberFirstSequence)
+		if AccountNumberFirstSequence < 0 {
+			panic(fmt.Sprintf("ACCOUNT_NUMBER_FIRST_SEQUENCE must not be negative, got %d", AccountNumberFirstSequence))
+		}
 	})
 }
 
@@ -37,4 +96,26 @@ func GetStringOrDefault(envName, defaultVal string) string {
 	return defaultVal
 }
 
+func GetIntOrDefault(envName string, defaultVal int) int {
+	value := os.Getenv(envName)
+	if value == "" {
+		return defaultVal
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be an integer, got %q", envName, value))
+	}
+	return i
+}
 
+func GetBoolOrDefault(envName string, defaultVal bool) bool {
+	value := os.Getenv(envName)
+	if value == "" {
+		return defaultVal
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be true or false, got %q", envName, value))
+	}
+	return b
+}
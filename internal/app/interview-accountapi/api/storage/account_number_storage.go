@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech/go-data/data"
+	"github.com/google/uuid"
+)
+
+const accountNumberAllocationTableName = `"AccountNumberAllocation"`
+
+// AccountNumberStorage hands out the sequence numbers account numbers are generated from. Each
+// organisation has a sequence of its own under each country and bank id.
+type AccountNumberStorage struct {
+	db Database
+}
+
+func NewAccountNumberStorage(db Database) *AccountNumberStorage {
+	return &AccountNumberStorage{db: db}
+}
+
+type allocation struct {
+	Sequence     int64         `db:"sequence"`
+	LastSequence sql.NullInt64 `db:"last_sequence"`
+}
+
+// Allocate takes the next sequence number of the bank id for the organisation, starting at first
+// unless a sequence was set up for it beforehand. It must run in a transaction: the row of the
+// sequence stays locked from its update until the transaction ends, so that concurrent creates
+// never take the same number. The upsert is split into an insert, an update and a select, as
+// RETURNING needs SQLite 3.35 and the vendored driver bundles 3.30.
+func (a *AccountNumberStorage) Allocate(organisationID uuid.UUID, country string, bankID string, first int64) (int64, error) {
+	key := squirrel.Eq{"organisation_id": organisationID, "country": country, "bank_id": bankID}
+
+	sqlStmt, params, err := data.Insert(accountNumberAllocationTableName).
+		Columns("organisation_id", "country", "bank_id", "next_sequence").
+		Values(organisationID, country, bankID, first).
+		Suffix("ON CONFLICT (organisation_id, country, bank_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := a.db.Exec(sqlStmt, params...); err != nil {
+		return 0, fmt.Errorf("database error - failed to allocate account number: %s", err)
+	}
+
+	sqlStmt, params, err = data.Update(accountNumberAllocationTableName).
+		Set("next_sequence", squirrel.Expr("next_sequence + 1")).
+		Where(key).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := a.db.Exec(sqlStmt, params...); err != nil {
+		return 0, fmt.Errorf("database error - failed to allocate account number: %s", err)
+	}
+
+	sqlStmt, params, err = data.Select("next_sequence - 1 AS sequence", "last_sequence").
+		From(accountNumberAllocationTableName).
+		Where(key).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var allocated allocation
+	if err := a.db.Get(&allocated, sqlStmt, params...); err != nil {
+		return 0, fmt.Errorf("database error - failed to allocate account number: %s", err)
+	}
+	if allocated.LastSequence.Valid && allocated.Sequence > allocated.LastSequence.Int64 {
+		return 0, errors.NewConflictError(fmt.Sprintf("account numbers of bank_id %s are exhausted", bankID))
+	}
+	return allocated.Sequence, nil
+}
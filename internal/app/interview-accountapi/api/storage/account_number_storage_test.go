@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	application_errors "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// allocationDatabase is a fresh in-memory database with the allocation table of the migrations,
+// applied up to their Down section.
+func allocationDatabase(t *testing.T) *sqlx.DB {
+	migration, err := ioutil.ReadFile("../migrations/sqlite3/004_account_number_allocation.sql")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db.SetMaxOpenConns(1)
+	up := strings.Split(string(migration), "-- +migrate Down")[0]
+	if _, err := db.Exec(up); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db
+}
+
+func TestAllocate(t *testing.T) {
+	db := allocationDatabase(t)
+	defer db.Close()
+	numbers := NewAccountNumberStorage(db)
+	organisation := uuid.New()
+
+	for _, expected := range []int64{1, 2, 3} {
+		sequence, err := numbers.Allocate(organisation, "GB", "400300", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sequence)
+	}
+
+	sequence, err := numbers.Allocate(organisation, "GB", "400301", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), sequence, "each bank id has a sequence of its own")
+
+	sequence, err = numbers.Allocate(uuid.New(), "GB", "400300", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), sequence, "each organisation has a sequence of its own")
+}
+
+func TestAllocateWithinConfiguredRange(t *testing.T) {
+	db := allocationDatabase(t)
+	defer db.Close()
+	numbers := NewAccountNumberStorage(db)
+	organisation := uuid.New()
+	db.MustExec(`INSERT INTO "AccountNumberAllocation" (organisation_id, country, bank_id, next_sequence, last_sequence) VALUES ($1, 'GB', '400300', 500, 501)`, organisation)
+
+	for _, expected := range []int64{500, 501} {
+		sequence, err := numbers.Allocate(organisation, "GB", "400300", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, sequence)
+	}
+
+	_, err := numbers.Allocate(organisation, "GB", "400300", 1)
+	assert.IsType(t, &application_errors.ConflictError{}, err)
+}
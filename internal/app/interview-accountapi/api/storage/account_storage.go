@@ -1,22 +1,68 @@
 package storage
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/Masterminds/squirrel"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/namematching"
 	"github.com/form3tech/go-data/data"
-	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
+	"github.com/google/uuid"
 )
 
-const accountTableName = `"Account"`
-const duplicationErrorCode = "23505"
+const accountTableName = "Account"
+
+// accountIdentityIndex keeps the country, bank id and account number of the accounts of an
+// organisation that are not deleted unique.
+const accountIdentityIndex = "account_identity"
+
+// RecordAttribute is the SQL expression reading an attribute, as text, out of the JSON record
+// column. SQLite stores the record as a blob, which its JSON functions only accept as text. The
+// expressions match those of the account_identity index, so that it serves lookups on them too.
+func RecordAttribute(driver string, attribute string) string {
+	if driver == "postgres" {
+		return fmt.Sprintf("record->>'%s'", attribute)
+	}
+	return fmt.Sprintf("json_extract(CAST(record AS TEXT), '$.%s')", attribute)
+}
+
+// MatchingAllowed selects the accounts that have not opted out of account matching. JSON booleans
+// read as the text true and false on PostgreSQL, and as 1 and 0 on SQLite.
+func MatchingAllowed(driver string) squirrel.Sqlizer {
+	optOut := RecordAttribute(driver, "account_matching_opt_out")
+	if driver == "postgres" {
+		return squirrel.Expr(fmt.Sprintf("COALESCE(%s, 'false') <> 'true'", optOut))
+	}
+	return squirrel.Expr(fmt.Sprintf("COALESCE(%s, 0) = 0", optOut))
+}
+
+// NameKeys is how the name keys of an account are stored: separated, and surrounded, by spaces,
+// so that MatchesNameKeys can look for each of them with LIKE.
+func NameKeys(account internalmodels.Account) string {
+	keys := namematching.Keys(account.Names()...)
+	if len(keys) == 0 {
+		return ""
+	}
+	return " " + strings.Join(keys, " ") + " "
+}
+
+// MatchesNameKeys selects the accounts sharing one of the keys, and those whose keys are not
+// stored yet.
+func MatchesNameKeys(keys []string) squirrel.Sqlizer {
+	matches := squirrel.Or{squirrel.Eq{"name_keys": nil}}
+	for _, key := range keys {
+		matches = append(matches, squirrel.Like{"name_keys": "% " + key + " %"})
+	}
+	return matches
+}
 
 type AccountStorage struct {
 	Storage
 }
 
-func NewAccountStorage(db *sqlx.DB) *AccountStorage {
+func NewAccountStorage(db Database) *AccountStorage {
 	return &AccountStorage{
 		Storage{
 			db:        db,
@@ -26,8 +72,8 @@ func NewAccountStorage(db *sqlx.DB) *AccountStorage {
 }
 
 func (a *AccountStorage) Create(record *internalmodels.AccountRecord) error {
-	sqlStmt, params, err := data.Insert(a.tableName).
-		Columns("id", "organisation_id", "version", "is_deleted", "is_locked", "created_on", "modified_on", "record").
+	sqlStmt, params, err := data.Insert(a.table()).
+		Columns("id", "organisation_id", "version", "is_deleted", "is_locked", "created_on", "modified_on", "record", "name_keys").
 		Values(
 			record.ID,
 			record.OrganisationID,
@@ -37,6 +83,7 @@ func (a *AccountStorage) Create(record *internalmodels.AccountRecord) error {
 			record.CreatedOn,
 			record.ModifiedOn,
 			record.Record,
+			NameKeys(record.Record),
 		).
 		ToSql()
 	if err != nil {
@@ -44,17 +91,161 @@ func (a *AccountStorage) Create(record *internalmodels.AccountRecord) error {
 	}
 	_, err = a.db.Exec(sqlStmt, params...)
 	if err != nil {
-		if dbErr, ok := err.(*pq.Error); ok && dbErr.Code == duplicationErrorCode {
-			return errors.NewDuplicateError("Account cannot be created as it violates a duplicate constraint")
+		if violatesIndex(err, accountIdentityIndex) {
+			return a.identityConflict(record, "Account cannot be created", err)
 		}
+		return translateError(err, "Account cannot be created")
+	}
+	return nil
+}
+
+func (a *AccountStorage) Get(id uuid.UUID) (*internalmodels.AccountRecord, error) {
+	record := &internalmodels.AccountRecord{}
+	if err := a.GetByID(id, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Update stores the new attributes of the account. The id of an account never changes, so the
+// only uniqueness an update can break is that of its identity.
+func (a *AccountStorage) Update(record *internalmodels.AccountRecord) error {
+	err := a.Storage.update(record, map[string]interface{}{"name_keys": NameKeys(record.Record)})
+	if _, ok := err.(*errors.DuplicateError); ok {
+		return a.identityConflict(record, "Account cannot be updated", err)
 	}
 	return err
 }
 
+// HasAccountNumber tells whether an account of the organisation that is not deleted already has
+// the country, bank id and account number.
+func (a *AccountStorage) HasAccountNumber(organisationID uuid.UUID, country string, bankID string, accountNumber string) (bool, error) {
+	driver := a.db.DriverName()
+	sqlStmt, params, err := data.Select("count(*)").From(a.table()).
+		Where(squirrel.And{
+			squirrel.Eq{"organisation_id": organisationID, "is_deleted": false},
+			squirrel.Eq{RecordAttribute(driver, "country"): country},
+			squirrel.Eq{RecordAttribute(driver, "bank_id"): bankID},
+			squirrel.Eq{RecordAttribute(driver, "account_number"): accountNumber},
+		}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	if err := a.db.Get(&count, sqlStmt, params...); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// identityConflict names the account that already has the country, bank id and account number of
+// record. It falls back to translating err when there is none, e.g. when it is record itself.
+func (a *AccountStorage) identityConflict(record *internalmodels.AccountRecord, action string, err error) error {
+	driver := a.db.DriverName()
+	sqlStmt, params, sqlErr := data.Select("id").From(a.table()).
+		Where(squirrel.And{
+			squirrel.Eq{"organisation_id": record.OrganisationID, "is_deleted": false},
+			squirrel.NotEq{"id": record.ID},
+			squirrel.Eq{RecordAttribute(driver, "country"): record.Record.Country},
+			squirrel.Eq{RecordAttribute(driver, "bank_id"): record.Record.BankID},
+			squirrel.Eq{RecordAttribute(driver, "account_number"): record.Record.AccountNumber},
+		}).
+		Limit(1).
+		ToSql()
+	if sqlErr != nil {
+		return sqlErr
+	}
+	var ids []uuid.UUID
+	if sqlErr := a.db.Select(&ids, sqlStmt, params...); sqlErr != nil || len(ids) == 0 {
+		return translateError(err, action)
+	}
+	return errors.NewDuplicateError(fmt.Sprintf("%s as account %s has the same country, bank_id and account_number", action, ids[0]))
+}
+
+// FillNameKeys stores the name keys of the accounts stored before they had any, a batch at a time.
+func (a *AccountStorage) FillNameKeys(batchSize int) (int, error) {
+	filled := 0
+	for {
+		sqlStmt, params, err := data.Select("*").From(a.table()).
+			Where(squirrel.Eq{"name_keys": nil}).
+			Limit(uint64(batchSize)).
+			ToSql()
+		if err != nil {
+			return filled, err
+		}
+		var records []*internalmodels.AccountRecord
+		if err := a.db.Select(&records, sqlStmt, params...); err != nil {
+			return filled, err
+		}
+		if len(records) == 0 {
+			return filled, nil
+		}
+		for _, record := range records {
+			sqlStmt, params, err := data.Update(a.table()).
+				Set("name_keys", NameKeys(record.Record)).
+				Where(squirrel.Eq{"id": record.ID}).
+				ToSql()
+			if err != nil {
+				return filled, err
+			}
+			if _, err := a.db.Exec(sqlStmt, params...); err != nil {
+				return filled, err
+			}
+			filled++
+		}
+	}
+}
+
+// Delete marks the account as deleted rather than removing it, so that it can still be looked up
+// and restored. Like Update, it bumps the version and fails if the account has moved on.
 func (a *AccountStorage) Delete(record *internalmodels.AccountRecord) error {
-	pred := squirrel.Eq{
-		"id":      record.ID,
-		"version": record.Version,
+	sqlStmt, params, err := data.Update(a.table()).
+		Set("is_deleted", true).
+		Set("modified_on", record.ModifiedOn).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{
+			"id":         record.ID,
+			"version":    record.Version,
+			"is_deleted": false,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	res, err := a.db.Exec(sqlStmt, params...)
+	if err != nil {
+		return fmt.Errorf("database error - failed to delete record: %s", err)
+	}
+	rows, err := res.RowsAffected()
+	if rows == 0 || err != nil {
+		return errors.NewConflictError(fmt.Sprintf("unable to delete expected version %d", *record.Version))
+	}
+	return nil
+}
+
+// SetLocked locks or unlocks the account, provided it is still at the expected version.
+func (a *AccountStorage) SetLocked(record *internalmodels.AccountRecord, locked bool) error {
+	sqlStmt, params, err := data.Update(a.table()).
+		Set("is_locked", locked).
+		Set("modified_on", record.ModifiedOn).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{
+			"id":         record.ID,
+			"version":    record.Version,
+			"is_deleted": false,
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	res, err := a.db.Exec(sqlStmt, params...)
+	if err != nil {
+		return fmt.Errorf("database error - failed to lock record: %s", err)
+	}
+	rows, err := res.RowsAffected()
+	if rows == 0 || err != nil {
+		return errors.NewConflictError(fmt.Sprintf("unable to lock expected version %d", *record.Version))
 	}
-	return a.DeleteRecord(pred)
+	return nil
 }
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/namematching"
+	"github.com/form3tech/go-data/data"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+)
+
+// accountDatabase is a fresh in-memory database with all the migrations applied, less the sample
+// account they insert.
+func accountDatabase(t *testing.T) *sqlx.DB {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := migrate.Exec(db.DB, "sqlite3", &migrate.FileMigrationSource{Dir: "../migrations/sqlite3"}, migrate.Up); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db.MustExec(`DELETE FROM "Account"`)
+	return db
+}
+
+// namedLike returns the ids of the accounts sharing a name key with name.
+func namedLike(t *testing.T, db *sqlx.DB, name string) []uuid.UUID {
+	sqlStmt, params, err := data.Select("id").From(`"Account"`).
+		Where(MatchesNameKeys(namematching.Keys(name))).
+		OrderBy("pagination_id").
+		ToSql()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var ids []uuid.UUID
+	if !assert.NoError(t, db.Select(&ids, sqlStmt, params...)) {
+		t.FailNow()
+	}
+	return ids
+}
+
+func TestNameKeys(t *testing.T) {
+	assert.Equal(t, " ho sa ", NameKeys(internalmodels.Account{BankAccountName: "Samantha Holder", AlternativeBankAccountNames: []string{"Sam Holder"}}))
+	assert.Equal(t, "", NameKeys(internalmodels.Account{}))
+}
+
+func TestAccountStorage_KeepsTheNameKeysInStepWithTheRecord(t *testing.T) {
+	db := accountDatabase(t)
+	accounts := NewAccountStorage(db)
+	account := newTestAccountRecord(uuid.New(), "41426819")
+	account.Record.BankAccountName = "Samantha Holder"
+	if !assert.NoError(t, accounts.Create(account)) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []uuid.UUID{account.ID}, namedLike(t, db, "Sam Holder"))
+	assert.Empty(t, namedLike(t, db, "Barry White"))
+
+	account.Record.BankAccountName = "Barry White"
+	if !assert.NoError(t, accounts.Update(account)) {
+		t.FailNow()
+	}
+
+	assert.Empty(t, namedLike(t, db, "Sam Holder"))
+	assert.Equal(t, []uuid.UUID{account.ID}, namedLike(t, db, "B White"))
+}
+
+func TestAccountStorage_FillNameKeys(t *testing.T) {
+	db := accountDatabase(t)
+	accounts := NewAccountStorage(db)
+	var ids []uuid.UUID
+	for _, accountNumber := range []string{"41426819", "41426820", "41426821"} {
+		account := newTestAccountRecord(uuid.New(), accountNumber)
+		account.Record.BankAccountName = "Samantha Holder"
+		if !assert.NoError(t, accounts.Create(account)) {
+			t.FailNow()
+		}
+		ids = append(ids, account.ID)
+	}
+	db.MustExec(`UPDATE "Account" SET name_keys = NULL`)
+	assert.Len(t, namedLike(t, db, "Barry White"), 3, "accounts without keys are always candidates")
+
+	filled, err := accounts.FillNameKeys(2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, filled)
+	assert.Empty(t, namedLike(t, db, "Barry White"))
+	assert.Equal(t, ids, namedLike(t, db, "Holder"))
+}
+
+func TestMatchingAllowed(t *testing.T) {
+	db := accountDatabase(t)
+	accounts := NewAccountStorage(db)
+	optedOut, optedIn := true, false
+	var allowed []uuid.UUID
+	for n, optOut := range []*bool{&optedOut, &optedIn, nil} {
+		account := newTestAccountRecord(uuid.New(), fmt.Sprintf("4142681%d", n))
+		account.Record.AccountMatchingOptOut = optOut
+		if !assert.NoError(t, accounts.Create(account)) {
+			t.FailNow()
+		}
+		if optOut == nil || !*optOut {
+			allowed = append(allowed, account.ID)
+		}
+	}
+	sqlStmt, params, err := data.Select("id").From(`"Account"`).
+		Where(MatchingAllowed(db.DriverName())).
+		OrderBy("pagination_id").
+		ToSql()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var ids []uuid.UUID
+
+	assert.NoError(t, db.Select(&ids, sqlStmt, params...))
+	assert.Equal(t, allowed, ids)
+}
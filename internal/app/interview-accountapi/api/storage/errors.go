@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	application_errors "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqNotNullViolation    = "23502"
+)
+
+type constraintViolation int
+
+const (
+	noViolation constraintViolation = iota
+	uniqueViolation
+	foreignKeyViolation
+	notNullViolation
+)
+
+// violationOf reports which constraint, if any, the sqlite3 or postgres driver error is about.
+func violationOf(err error) constraintViolation {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqUniqueViolation:
+			return uniqueViolation
+		case pqForeignKeyViolation:
+			return foreignKeyViolation
+		case pqNotNullViolation:
+			return notNullViolation
+		}
+		return noViolation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return uniqueViolation
+		case sqlite3.ErrConstraintForeignKey:
+			return foreignKeyViolation
+		case sqlite3.ErrConstraintNotNull:
+			return notNullViolation
+		}
+	}
+	return noViolation
+}
+
+// violatesIndex reports whether the error is a unique violation of the given index.
+func violatesIndex(err error, index string) bool {
+	if violationOf(err) != uniqueViolation {
+		return false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return strings.EqualFold(pqErr.Constraint, index)
+	}
+	// SQLite names the index in the message, e.g. "UNIQUE constraint failed: index 'Account_identity'"
+	return strings.Contains(strings.ToLower(err.Error()), fmt.Sprintf("'%s'", strings.ToLower(index)))
+}
+
+// translateError turns a constraint violation into the matching api error, its message starting
+// with action, e.g. "Account cannot be created". Any other error is returned as it is.
+func translateError(err error, action string) error {
+	switch violationOf(err) {
+	case uniqueViolation:
+		return application_errors.NewDuplicateError(fmt.Sprintf("%s as it violates a duplicate constraint", action))
+	case foreignKeyViolation:
+		return application_errors.NewIllegalArgumentError(fmt.Sprintf("%s as it refers to a record that does not exist", action))
+	case notNullViolation:
+		return application_errors.NewIllegalArgumentError(fmt.Sprintf("%s as a required value is missing", action))
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	application_errors "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// sqliteError runs the statements against a fresh in-memory database with foreign keys enforced
+// and returns the error of the last one.
+func sqliteError(t *testing.T, statements ...string) error {
+	db, err := sqlx.Connect("sqlite3", "file::memory:?_foreign_keys=1")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	schema := []string{
+		`CREATE TABLE "Parent" (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE "Child" (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES "Parent" (id), name TEXT NOT NULL, code TEXT UNIQUE)`,
+		`INSERT INTO "Parent" (id) VALUES (1)`,
+		`INSERT INTO "Child" (id, parent_id, name, code) VALUES (1, 1, 'first', 'A')`,
+		`CREATE UNIQUE INDEX Child_identity ON "Child" (parent_id, lower(name))`,
+	}
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); !assert.NoError(t, err) {
+			t.FailNow()
+		}
+	}
+	for _, statement := range statements {
+		_, err = db.Exec(statement)
+	}
+	return err
+}
+
+func TestTranslateError(t *testing.T) {
+	plain := fmt.Errorf("connection refused")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected interface{}
+	}{
+		{"postgres unique violation", &pq.Error{Code: pqUniqueViolation}, &application_errors.DuplicateError{}},
+		{"postgres foreign key violation", &pq.Error{Code: pqForeignKeyViolation}, &application_errors.IllegalArgumentError{}},
+		{"postgres not null violation", &pq.Error{Code: pqNotNullViolation}, &application_errors.IllegalArgumentError{}},
+		{"postgres other error", &pq.Error{Code: "42P01"}, nil},
+		{"sqlite primary key violation", sqliteError(t, `INSERT INTO "Child" (id, parent_id, name) VALUES (1, 1, 'again')`), &application_errors.DuplicateError{}},
+		{"sqlite unique violation", sqliteError(t, `INSERT INTO "Child" (id, parent_id, name, code) VALUES (2, 1, 'second', 'A')`), &application_errors.DuplicateError{}},
+		{"sqlite foreign key violation", sqliteError(t, `INSERT INTO "Child" (id, parent_id, name) VALUES (2, 2, 'orphan')`), &application_errors.IllegalArgumentError{}},
+		{"sqlite not null violation", sqliteError(t, `INSERT INTO "Child" (id, parent_id) VALUES (2, 1)`), &application_errors.IllegalArgumentError{}},
+		{"sqlite other error", sqliteError(t, `INSERT INTO "Missing" (id) VALUES (1)`), nil},
+		{"other error", plain, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !assert.Error(t, tt.err) {
+				return
+			}
+			translated := translateError(tt.err, "Record cannot be saved")
+			if tt.expected == nil {
+				assert.Equal(t, tt.err, translated)
+				return
+			}
+			assert.IsType(t, tt.expected, translated)
+			assert.Contains(t, translated.Error(), "Record cannot be saved")
+		})
+	}
+}
+
+func TestViolatesIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"postgres violation of the index", &pq.Error{Code: pqUniqueViolation, Constraint: "child_identity"}, true},
+		{"postgres violation of another index", &pq.Error{Code: pqUniqueViolation, Constraint: "child_pkey"}, false},
+		{"postgres other error", &pq.Error{Code: pqNotNullViolation, Constraint: "child_identity"}, false},
+		{"sqlite violation of the index", sqliteError(t, `INSERT INTO "Child" (id, parent_id, name) VALUES (2, 1, 'FIRST')`), true},
+		{"sqlite violation of another index", sqliteError(t, `INSERT INTO "Child" (id, parent_id, name, code) VALUES (2, 1, 'second', 'A')`), false},
+		{"sqlite other error", sqliteError(t, `INSERT INTO "Child" (id, parent_id) VALUES (2, 1)`), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, violatesIndex(tt.err, "Child_identity"))
+		})
+	}
+}
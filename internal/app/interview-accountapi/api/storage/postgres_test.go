@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	application_errors "github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/errors"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+)
+
+// postgresDatabase connects to the PostgreSQL database of DB_DSN when DB_DRIVER is postgres, and
+// skips the test otherwise. The migrations are applied to a schema of the test's own, dropped
+// once it is done, along with an AccountExtension table referring to the accounts, as nothing in
+// the migrations does yet.
+func postgresDatabase(t *testing.T) *sqlx.DB {
+	if os.Getenv("DB_DRIVER") != "postgres" || os.Getenv("DB_DSN") == "" {
+		t.Skip("set DB_DRIVER=postgres and DB_DSN to test against PostgreSQL")
+	}
+	db, err := sqlx.Connect("postgres", os.Getenv("DB_DSN"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	// the search path is set per connection
+	db.SetMaxOpenConns(1)
+	schema := fmt.Sprintf("storage_test_%s", strings.Replace(uuid.New().String(), "-", "", -1))
+	db.MustExec(fmt.Sprintf("CREATE SCHEMA %s", schema))
+	db.MustExec(fmt.Sprintf("SET search_path TO %s", schema))
+	t.Cleanup(func() {
+		db.MustExec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		_ = db.Close()
+	})
+
+	if _, err := migrate.Exec(db.DB, "postgres", &migrate.FileMigrationSource{Dir: "../migrations/postgres"}, migrate.Up); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	db.MustExec(`CREATE TABLE "AccountExtension" (
+		id             UUID NOT NULL REFERENCES "Account" (id),
+		organisationid UUID NOT NULL,
+		version        INT  NOT NULL,
+		isdeleted      INT  NOT NULL,
+		islocked       INT  NOT NULL,
+		createdon      TIMESTAMP,
+		modifiedon     TIMESTAMP,
+		record         JSONB
+	)`)
+	return db
+}
+
+func newTestAccountRecord(organisationID uuid.UUID, accountNumber string) *internalmodels.AccountRecord {
+	version := int64(0)
+	country, bankID := "GB", "400300"
+	return &internalmodels.AccountRecord{
+		ID:             uuid.New(),
+		OrganisationID: organisationID,
+		Version:        &version,
+		CreatedOn:      time.Now(),
+		ModifiedOn:     time.Now(),
+		Record:         internalmodels.Account{Country: &country, BankID: bankID, AccountNumber: accountNumber},
+	}
+}
+
+func TestPostgres_CreatingAnAccountTwiceIsADuplicate(t *testing.T) {
+	accounts := NewAccountStorage(postgresDatabase(t))
+	account := newTestAccountRecord(uuid.New(), "41426819")
+	if !assert.NoError(t, accounts.Create(account)) {
+		t.FailNow()
+	}
+
+	err := accounts.Create(account)
+
+	assert.IsType(t, &application_errors.DuplicateError{}, err)
+	assert.Contains(t, err.Error(), "Account cannot be created as it violates a duplicate constraint")
+}
+
+func TestPostgres_CreatingAnAccountWithTheIdentityOfAnotherNamesIt(t *testing.T) {
+	accounts := NewAccountStorage(postgresDatabase(t))
+	organisationID := uuid.New()
+	first := newTestAccountRecord(organisationID, "41426819")
+	if !assert.NoError(t, accounts.Create(first)) {
+		t.FailNow()
+	}
+
+	err := accounts.Create(newTestAccountRecord(organisationID, "41426819"))
+
+	assert.IsType(t, &application_errors.DuplicateError{}, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("as account %s has the same country, bank_id and account_number", first.ID))
+}
+
+func TestPostgres_CreatingAnAccountWithoutAVersionIsIllegal(t *testing.T) {
+	accounts := NewAccountStorage(postgresDatabase(t))
+	account := newTestAccountRecord(uuid.New(), "41426819")
+	account.Version = nil
+
+	err := accounts.Create(account)
+
+	assert.IsType(t, &application_errors.IllegalArgumentError{}, err)
+	assert.Contains(t, err.Error(), "as a required value is missing")
+}
+
+func TestPostgres_AddingARecordReferringToNoAccountIsIllegal(t *testing.T) {
+	db := postgresDatabase(t)
+	extensions := &Storage{db: db, tableName: "AccountExtension"}
+
+	err := extensions.Add(newTestAccountRecord(uuid.New(), "41426819"))
+
+	assert.IsType(t, &application_errors.IllegalArgumentError{}, err)
+	assert.Contains(t, err.Error(), "as it refers to a record that does not exist")
+}
+
+func TestPostgres_AddingARecordReferringToAnAccount(t *testing.T) {
+	db := postgresDatabase(t)
+	account := newTestAccountRecord(uuid.New(), "41426819")
+	if !assert.NoError(t, NewAccountStorage(db).Create(account)) {
+		t.FailNow()
+	}
+	extensions := &Storage{db: db, tableName: "AccountExtension"}
+
+	assert.NoError(t, extensions.Add(account))
+}
@@ -12,11 +12,8 @@ import (
 	"github.com/go-openapi/strfmt"
 	uuid "github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
 )
 
-const unique_violation = "23505"
-
 type genericRecord struct {
 	ID             interface{}
 	OrganisationID interface{}
@@ -26,15 +23,29 @@ type genericRecord struct {
 	Record         interface{}
 }
 
+// Database is what storage runs its statements on: the database itself, or a transaction when
+// several statements must succeed or fail together.
+type Database interface {
+	sqlx.Ext
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
+// Storage stores records in the table named tableName, which is quoted in the statements as
+// table names are case sensitive.
 type Storage struct {
-	db        *sqlx.DB
+	db        Database
 	tableName string
 }
 
+func (s *Storage) table() string {
+	return `"` + s.tableName + `"`
+}
+
 func (s *Storage) GetByID(ID uuid.UUID, result interface{}) error {
 	sqlStmt, params, err := data.
 		Select("*").
-		From("\"" + s.tableName + "\"").
+		From(s.table()).
 		Where(sq.Eq{"id": ID.String()}).ToSql()
 
 	if err != nil {
@@ -57,7 +68,7 @@ func (s *Storage) GetByID(ID uuid.UUID, result interface{}) error {
 func (s *Storage) CheckExists(ID strfmt.UUID) (bool, error) {
 	sqlStmt, params, err := data.
 		Select("count(*)").
-		From("\"" + s.tableName + "\"").
+		From(s.table()).
 		Where(sq.Eq{"id": ID}).ToSql()
 
 	if err != nil {
@@ -149,7 +160,7 @@ func (s *Storage) Add(dataRecord interface{}) error {
 		return err
 	}
 
-	sqlStmt, params, err := data.Insert("\""+s.tableName+"\"").
+	sqlStmt, params, err := data.Insert(s.table()).
 		Columns("id", "organisationid", "version", "isdeleted", "islocked", "createdon", "modifiedon", "record").
 		Values(recordDetails.ID, recordDetails.OrganisationID, 0, 0, 0, recordDetails.CreatedOn, recordDetails.ModifiedOn, recordDetails.Record).
 		ToSql()
@@ -160,9 +171,8 @@ func (s *Storage) Add(dataRecord interface{}) error {
 
 	_, err = s.db.Exec(sqlStmt, params...)
 	if err != nil {
-		pqError, ok := err.(*pq.Error)
-		if ok && pqError.Code == unique_violation {
-			return application_errors.NewDuplicateError("Cannot insert duplicate record")
+		if violationOf(err) != noViolation {
+			return translateError(err, "Cannot insert record")
 		}
 
 		return fmt.Errorf("could not insert Report, error: %v", err)
@@ -172,14 +182,21 @@ func (s *Storage) Add(dataRecord interface{}) error {
 }
 
 func (s *Storage) Update(dataRecord interface{}) error {
+	return s.update(dataRecord, nil)
+}
+
+// update stores the record, along with the values of the columns, which derive from it.
+func (s *Storage) update(dataRecord interface{}, columns map[string]interface{}) error {
 	recordDetails, err := s.getGenericRecord(dataRecord)
 	if err != nil {
 		return err
 	}
 
-	sqlStmt, params, err := data.Update("\""+s.tableName+"\"").
+	sqlStmt, params, err := data.Update(s.table()).
 		Set("record", recordDetails.Record).
+		Set("modified_on", recordDetails.ModifiedOn).
 		Set("version", sq.Expr("version + 1")).
+		SetMap(columns).
 		Where(sq.Eq{"id": recordDetails.ID, "version": recordDetails.Version}).
 		ToSql()
 
@@ -189,9 +206,8 @@ func (s *Storage) Update(dataRecord interface{}) error {
 
 	res, err := s.db.Exec(sqlStmt, params...)
 	if err != nil {
-		pqError, ok := err.(*pq.Error)
-		if ok && pqError.Code == unique_violation {
-			return application_errors.NewDuplicateError("Cannot insert duplicate record")
+		if violationOf(err) != noViolation {
+			return translateError(err, "Cannot update record")
 		}
 
 		return fmt.Errorf("could not insert Report, error: %v", err)
@@ -210,7 +226,7 @@ func (s *Storage) Update(dataRecord interface{}) error {
 }
 
 func (s *Storage) DeleteRecord(predicate interface{}) error {
-	sqlStm, params, err := data.Delete(s.tableName).
+	sqlStm, params, err := data.Delete(s.table()).
 		Where(predicate).
 		ToSql()
 	if err != nil {
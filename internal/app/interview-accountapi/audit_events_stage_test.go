@@ -0,0 +1,158 @@
+package interview_accountapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/eventsinks"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var dispatchedEvents struct {
+	sync.Mutex
+	events []*internalmodels.Form3Event
+}
+
+// recordDispatchedEvents keeps every Form3 event published by the service, so tests can look them up.
+func recordDispatchedEvents(sender *eventsinks.ChannelSender) {
+	for message := range sender.Messages() {
+		dispatchedEvents.Lock()
+		dispatchedEvents.events = append(dispatchedEvents.events, message.Body.(*internalmodels.Form3Event))
+		dispatchedEvents.Unlock()
+	}
+}
+
+// dispatchedEventOf waits for the event of the record at the given version to be received.
+func dispatchedEventOf(recordId uuid.UUID, version int64) *internalmodels.Form3Event {
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		dispatchedEvents.Lock()
+		for _, event := range dispatchedEvents.events {
+			if event.Record.RecordId == recordId && event.Version == version {
+				dispatchedEvents.Unlock()
+				return event
+			}
+		}
+		dispatchedEvents.Unlock()
+	}
+	return nil
+}
+
+type auditEventsStage struct {
+	t              *testing.T
+	organisationId uuid.UUID
+	client         *accountapi.Client
+	account        *accountapi.Account
+	event          *internalmodels.Form3Event
+}
+
+func AuditEventsTest(t *testing.T) (*auditEventsStage, *auditEventsStage, *auditEventsStage) {
+	stage := &auditEventsStage{
+		t:              t,
+		organisationId: uuid.New(),
+	}
+	return stage, stage, stage
+}
+
+func (s *auditEventsStage) and() *auditEventsStage {
+	return s
+}
+
+func (s *auditEventsStage) an_account_api_client() *auditEventsStage {
+	token := newTestToken(s.organisationId, accountPermissions(AuthoriseAllActions...))
+	s.client = newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort), token)
+	return s
+}
+
+func (s *auditEventsStage) an_account_is_created() *auditEventsStage {
+	account, err := s.client.Create(context.Background(), newTestAccount(s.organisationId.String(), "41426819", "400300"))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.account = account
+	return s
+}
+
+func (s *auditEventsStage) the_account_is_updated() *auditEventsStage {
+	_, err := s.client.Update(context.Background(), s.account.ID, 0, map[string]interface{}{
+		"bank_account_name": "Samantha Baker",
+	})
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *auditEventsStage) the_account_is_deleted() *auditEventsStage {
+	if !assert.NoError(s.t, s.client.Delete(context.Background(), s.account.ID, 0)) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *auditEventsStage) the_account_is_locked() *auditEventsStage {
+	_, err := s.client.Lock(context.Background(), s.account.ID, 0)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+// an_event_is_dispatched finds the event of the account at the given version, and checks its type.
+func (s *auditEventsStage) an_event_is_dispatched(eventType string, description string, version int64) *auditEventsStage {
+	s.event = dispatchedEventOf(uuid.MustParse(s.account.ID), version)
+	if !assert.NotNil(s.t, s.event, "no event dispatched for version %d", version) {
+		s.t.FailNow()
+	}
+	assert.Equal(s.t, eventType, s.event.EventType)
+	assert.Equal(s.t, description, s.event.Record.Description)
+	assert.Equal(s.t, "accounts", s.event.RecordType)
+	assert.Equal(s.t, s.organisationId, s.event.OrganisationId)
+	assert.JSONEq(s.t, string(s.event.Record.AfterData), string(s.event.Data))
+	return s
+}
+
+func (s *auditEventsStage) the_event_is_actioned_by_the_user() *auditEventsStage {
+	assert.Equal(s.t, testUserId, s.event.Record.ActionedBy)
+	return s
+}
+
+func (s *auditEventsStage) the_event_has_no_before_data() *auditEventsStage {
+	assert.Empty(s.t, s.event.Record.BeforeData)
+	return s
+}
+
+func (s *auditEventsStage) the_event_before_data_is_at_version(version int) *auditEventsStage {
+	assert.Equal(s.t, version, s.snapshot(s.event.Record.BeforeData).Version)
+	return s
+}
+
+func (s *auditEventsStage) the_event_after_data_is_at_version(version int) *auditEventsStage {
+	assert.Equal(s.t, version, s.snapshot(s.event.Record.AfterData).Version)
+	return s
+}
+
+func (s *auditEventsStage) the_event_after_data_has_bank_account_name(name string) *auditEventsStage {
+	assert.Equal(s.t, name, s.snapshot(s.event.Record.AfterData).Attributes.BankAccountName)
+	return s
+}
+
+func (s *auditEventsStage) the_event_after_data_is_deleted() *auditEventsStage {
+	assert.True(s.t, s.snapshot(s.event.Record.AfterData).Deleted)
+	return s
+}
+
+func (s *auditEventsStage) snapshot(data json.RawMessage) accountapi.Account {
+	account := accountapi.Account{}
+	if !assert.NoError(s.t, json.Unmarshal(data, &account)) {
+		s.t.FailNow()
+	}
+	assert.Equal(s.t, s.account.ID, account.ID)
+	return account
+}
@@ -0,0 +1,68 @@
+package interview_accountapi
+
+import "testing"
+
+func TestAcc_AuditEvents_Created(t *testing.T) {
+	given, when, then := AuditEventsTest(t)
+
+	given.
+		an_account_api_client()
+
+	when.
+		an_account_is_created()
+
+	then.
+		an_event_is_dispatched("created", "Record inserted", 0).and().
+		the_event_has_no_before_data().and().
+		the_event_after_data_is_at_version(0).and().
+		the_event_is_actioned_by_the_user()
+}
+
+func TestAcc_AuditEvents_Updated(t *testing.T) {
+	given, when, then := AuditEventsTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_account_is_created()
+
+	when.
+		the_account_is_updated()
+
+	then.
+		an_event_is_dispatched("updated", "Record updated", 1).and().
+		the_event_before_data_is_at_version(0).and().
+		the_event_after_data_is_at_version(1).and().
+		the_event_after_data_has_bank_account_name("Samantha Baker")
+}
+
+func TestAcc_AuditEvents_Deleted(t *testing.T) {
+	given, when, then := AuditEventsTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_account_is_created()
+
+	when.
+		the_account_is_deleted()
+
+	then.
+		an_event_is_dispatched("deleted", "Record deleted", 1).and().
+		the_event_before_data_is_at_version(0).and().
+		the_event_after_data_is_deleted()
+}
+
+func TestAcc_AuditEvents_Locked(t *testing.T) {
+	given, when, then := AuditEventsTest(t)
+
+	given.
+		an_account_api_client().and().
+		an_account_is_created()
+
+	when.
+		the_account_is_locked()
+
+	then.
+		an_event_is_dispatched("updated", "Account locked", 1).and().
+		the_event_before_data_is_at_version(0).and().
+		the_event_after_data_is_at_version(1)
+}
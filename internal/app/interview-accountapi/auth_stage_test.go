@@ -0,0 +1,196 @@
+package interview_accountapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/form3tech/go-security/security"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type authStage struct {
+	t                   *testing.T
+	baseURL             string
+	organisationId      uuid.UUID
+	otherOrganisationId uuid.UUID
+	client              *accountapi.Client
+	account             *accountapi.Account
+	otherAccount        *accountapi.Account
+	listedAccounts      *accountapi.AccountListData
+	error               error
+}
+
+func AuthTest(t *testing.T) (*authStage, *authStage, *authStage) {
+	stage := &authStage{
+		t:                   t,
+		baseURL:             fmt.Sprintf("http://localhost:%d", ServerPort),
+		organisationId:      uuid.New(),
+		otherOrganisationId: uuid.New(),
+	}
+	return stage, stage, stage
+}
+
+func (s *authStage) and() *authStage {
+	return s
+}
+
+func (s *authStage) a_user_without_a_token() *authStage {
+	client, err := accountapi.NewClient(s.baseURL)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.client = client
+	return s
+}
+
+func (s *authStage) a_user_with_a_token_signed_with_another_key() *authStage {
+	otherKeyPair, err := security.GenerateTestKeyPair()
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	token, err := security.NewJwtToken(otherKeyPair.RsaPrivateKey, testUserId).
+		ForOrganisations(s.organisationId).
+		WithAclUrl(aclServer.URL).
+		Build()
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.client = newAccountClient(s.t, s.baseURL, token)
+	return s
+}
+
+// a_user_with_a_token_of_user_id gives the user a token signed with the key of the server, allowed
+// to do anything with the accounts of the organisation, whose user id claim is userId, or is left
+// out when userId is nil.
+func (s *authStage) a_user_with_a_token_of_user_id(userId interface{}) *authStage {
+	acls, err := security.EncodeAcls([]uuid.UUID{s.organisationId}, []security.Permission{accountPermissions(AuthoriseAllActions...)})
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	aclId := uuid.New().String()
+	testAcls.Store(aclId, acls)
+
+	claims := jwt.MapClaims{
+		"https://form3.tech/acl_url": fmt.Sprintf("%s/%s", aclServer.URL, aclId),
+		"exp":                        time.Now().Add(10 * time.Minute).Unix(),
+	}
+	if userId != nil {
+		claims["https://form3.tech/user_id"] = userId
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(testKeyPair.RsaPrivateKey)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.client = newAccountClient(s.t, s.baseURL, token)
+	return s
+}
+
+func (s *authStage) a_user_allowed_to(actions ...security.AuthoriseAction) *authStage {
+	s.client = newAccountClient(s.t, s.baseURL, newTestToken(s.organisationId, accountPermissions(actions...)))
+	return s
+}
+
+func (s *authStage) an_account_of_the_organisation() *authStage {
+	owner := newAccountClient(s.t, s.baseURL, newTestToken(s.organisationId, accountPermissions(AuthoriseAllActions...)))
+	account, err := owner.Create(context.Background(), newTestAccount(s.organisationId.String(), "41426819", "400300"))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.account = account
+	return s
+}
+
+func (s *authStage) an_account_of_another_organisation() *authStage {
+	owner := newAccountClient(s.t, s.baseURL, newTestToken(s.otherOrganisationId, accountPermissions(AuthoriseAllActions...)))
+	account, err := owner.Create(context.Background(), newTestAccount(s.otherOrganisationId.String(), "41426819", "400300"))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.otherAccount = account
+	return s
+}
+
+func (s *authStage) listing_the_accounts() *authStage {
+	s.listedAccounts, s.error = s.client.List(context.Background(), accountapi.ListOptions{PageSize: 100})
+	return s
+}
+
+func (s *authStage) listing_the_accounts_of_the_other_organisation() *authStage {
+	s.listedAccounts, s.error = s.client.List(context.Background(), accountapi.ListOptions{
+		OrganisationIDs: []string{s.otherOrganisationId.String()},
+	})
+	return s
+}
+
+func (s *authStage) fetching_the_account_of_the_organisation() *authStage {
+	_, s.error = s.client.Fetch(context.Background(), s.account.ID)
+	return s
+}
+
+func (s *authStage) fetching_the_account_of_the_other_organisation() *authStage {
+	_, s.error = s.client.Fetch(context.Background(), s.otherAccount.ID)
+	return s
+}
+
+func (s *authStage) creating_an_account_for_the_organisation() *authStage {
+	_, s.error = s.client.Create(context.Background(), newTestAccount(s.organisationId.String(), "41426819", "400300"))
+	return s
+}
+
+func (s *authStage) creating_an_account_for_another_organisation() *authStage {
+	_, s.error = s.client.Create(context.Background(), newTestAccount(s.otherOrganisationId.String(), "41426819", "400300"))
+	return s
+}
+
+func (s *authStage) deleting_the_account_of_the_organisation() *authStage {
+	s.error = s.client.Delete(context.Background(), s.account.ID, 0)
+	return s
+}
+
+func (s *authStage) no_error_is_returned() *authStage {
+	assert.NoError(s.t, s.error)
+	return s
+}
+
+func (s *authStage) the_error_is(expected error, statusCode int) *authStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+
+	var errorResponse *accountapi.ErrorResponse
+	if errors.As(s.error, &errorResponse) {
+		assert.Equal(s.t, statusCode, errorResponse.StatusCode)
+	}
+	return s
+}
+
+func (s *authStage) no_account_is_stored() *authStage {
+	owner := newAccountClient(s.t, s.baseURL, newTestToken(s.organisationId, accountPermissions(AuthoriseAllActions...)))
+	accounts, err := owner.List(context.Background(), accountapi.ListOptions{})
+	if assert.NoError(s.t, err) {
+		assert.Empty(s.t, accounts.Data)
+	}
+	return s
+}
+
+func (s *authStage) only_the_account_of_the_organisation_is_listed() *authStage {
+	if !assert.NoError(s.t, s.error) {
+		return s
+	}
+	if assert.Len(s.t, s.listedAccounts.Data, 1) {
+		assert.Equal(s.t, s.account.ID, s.listedAccounts.Data[0].ID)
+	}
+	return s
+}
+
+func (s *authStage) no_account_is_listed() *authStage {
+	if !assert.NoError(s.t, s.error) {
+		return s
+	}
+	assert.Empty(s.t, s.listedAccounts.Data)
+	return s
+}
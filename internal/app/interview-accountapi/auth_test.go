@@ -0,0 +1,160 @@
+package interview_accountapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/form3tech/go-security/security"
+)
+
+func TestAcc_Auth_RequestWithoutToken(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_without_a_token()
+
+	when.
+		listing_the_accounts()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusUnauthorized)
+}
+
+func TestAcc_Auth_TokenSignedWithAnotherKey(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_with_a_token_signed_with_another_key()
+
+	when.
+		listing_the_accounts()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusUnauthorized)
+}
+
+func TestAcc_Auth_TokenWithAUserIdThatIsNotAUUID(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_with_a_token_of_user_id("admin")
+
+	when.
+		creating_an_account_for_the_organisation()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusUnauthorized).and().
+		no_account_is_stored()
+}
+
+func TestAcc_Auth_TokenWithoutAUserId(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_with_a_token_of_user_id(nil)
+
+	when.
+		creating_an_account_for_the_organisation()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusUnauthorized).and().
+		no_account_is_stored()
+}
+
+func TestAcc_Auth_FetchAccountOfAnotherOrganisation(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		an_account_of_another_organisation().and().
+		a_user_allowed_to(AuthoriseAllActions...)
+
+	when.
+		fetching_the_account_of_the_other_organisation()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusForbidden)
+}
+
+func TestAcc_Auth_CreateAccountForAnotherOrganisation(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_allowed_to(AuthoriseAllActions...)
+
+	when.
+		creating_an_account_for_another_organisation()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusForbidden)
+}
+
+func TestAcc_Auth_ListOnlyReturnsAccountsOfOwnOrganisation(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		an_account_of_another_organisation().and().
+		a_user_allowed_to(AuthoriseAllActions...).and().
+		an_account_of_the_organisation()
+
+	when.
+		listing_the_accounts()
+
+	then.
+		only_the_account_of_the_organisation_is_listed()
+}
+
+func TestAcc_Auth_ListFilteredOnAnotherOrganisation(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		an_account_of_another_organisation().and().
+		a_user_allowed_to(AuthoriseAllActions...)
+
+	when.
+		listing_the_accounts_of_the_other_organisation()
+
+	then.
+		no_account_is_listed()
+}
+
+func TestAcc_Auth_ReadOnlyUserCanFetch(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_allowed_to(security.READ).and().
+		an_account_of_the_organisation()
+
+	when.
+		fetching_the_account_of_the_organisation()
+
+	then.
+		no_error_is_returned()
+}
+
+func TestAcc_Auth_ReadOnlyUserCannotCreate(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_allowed_to(security.READ)
+
+	when.
+		creating_an_account_for_the_organisation()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusForbidden)
+}
+
+func TestAcc_Auth_ReadOnlyUserCannotDelete(t *testing.T) {
+	given, when, then := AuthTest(t)
+
+	given.
+		a_user_allowed_to(security.READ).and().
+		an_account_of_the_organisation()
+
+	when.
+		deleting_the_account_of_the_organisation()
+
+	then.
+		the_error_is(accountapi.ErrForbidden, http.StatusForbidden)
+}
@@ -0,0 +1,160 @@
+package interview_accountapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	payeeBankID        = "400300"
+	payeeAccountNumber = "71268996"
+)
+
+type confirmationOfPayeeStage struct {
+	t                   *testing.T
+	organisationId      uuid.UUID
+	otherOrganisationId uuid.UUID
+	client              *accountapi.Client
+	account             accountapi.Account
+	confirmation        *accountapi.PayeeConfirmation
+	statusCode          int
+	error               error
+}
+
+func ConfirmationOfPayeeTest(t *testing.T) (*confirmationOfPayeeStage, *confirmationOfPayeeStage, *confirmationOfPayeeStage) {
+	organisationId := uuid.New()
+	stage := &confirmationOfPayeeStage{
+		t:              t,
+		organisationId: organisationId,
+		client: newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort),
+			newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))),
+		account: newTestAccount(organisationId.String(), payeeAccountNumber, payeeBankID),
+	}
+	return stage, stage, stage
+}
+
+func (s *confirmationOfPayeeStage) and() *confirmationOfPayeeStage {
+	return s
+}
+
+func (s *confirmationOfPayeeStage) a_personal_account_named(name string, alternativeNames ...string) *confirmationOfPayeeStage {
+	s.account.Attributes.BankAccountName = name
+	s.account.Attributes.AlternativeBankAccountNames = alternativeNames
+	return s.the_account_is_created()
+}
+
+func (s *confirmationOfPayeeStage) a_business_account_named(name string) *confirmationOfPayeeStage {
+	s.account.Attributes.AccountClassification = accountapi.AccountClassificationBusiness
+	return s.a_personal_account_named(name)
+}
+
+func (s *confirmationOfPayeeStage) a_joint_account_named(name string) *confirmationOfPayeeStage {
+	s.account.Attributes.JointAccount = true
+	return s.a_personal_account_named(name)
+}
+
+func (s *confirmationOfPayeeStage) an_account_opted_out_of_matching_named(name string) *confirmationOfPayeeStage {
+	s.account.Attributes.AccountMatchingOptOut = true
+	return s.a_personal_account_named(name)
+}
+
+func (s *confirmationOfPayeeStage) another_organisation_holds_the_account_number_for(name string) *confirmationOfPayeeStage {
+	s.otherOrganisationId = uuid.New()
+	account := newTestAccount(s.otherOrganisationId.String(), payeeAccountNumber, payeeBankID)
+	account.Attributes.BankAccountName = name
+	client := newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort),
+		newTestToken(s.otherOrganisationId, accountPermissions(AuthoriseAllActions...)))
+	_, err := client.Create(context.Background(), account)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *confirmationOfPayeeStage) the_account_is_created() *confirmationOfPayeeStage {
+	_, err := s.client.Create(context.Background(), s.account)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *confirmationOfPayeeStage) checking_a_personal_payee_named(name string) *confirmationOfPayeeStage {
+	return s.checking_payee(accountapi.AccountTypePersonal, payeeAccountNumber, name)
+}
+
+func (s *confirmationOfPayeeStage) checking_a_business_payee_named(name string) *confirmationOfPayeeStage {
+	return s.checking_payee(accountapi.AccountTypeBusiness, payeeAccountNumber, name)
+}
+
+func (s *confirmationOfPayeeStage) checking_another_account_number_for(name string) *confirmationOfPayeeStage {
+	return s.checking_payee(accountapi.AccountTypePersonal, "71268997", name)
+}
+
+func (s *confirmationOfPayeeStage) checking_payee(accountType accountapi.AccountType, accountNumber string, name string) *confirmationOfPayeeStage {
+	s.confirmation, s.error = s.client.ConfirmPayee(context.Background(), accountapi.PayeeCheck{
+		BankID:        payeeBankID,
+		AccountNumber: accountNumber,
+		AccountType:   accountType,
+		Name:          name,
+	})
+	return s
+}
+
+func (s *confirmationOfPayeeStage) another_organisation_is_checking_a_personal_payee_named(name string) *confirmationOfPayeeStage {
+	s.client = newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort),
+		newTestToken(uuid.New(), accountPermissions(AuthoriseAllActions...)))
+	return s.checking_a_personal_payee_named(name)
+}
+
+func (s *confirmationOfPayeeStage) both_organisations_are_checking_a_personal_payee_named(name string) *confirmationOfPayeeStage {
+	s.client = newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort),
+		newTestTokenForOrganisations([]uuid.UUID{s.organisationId, s.otherOrganisationId}, accountPermissions(AuthoriseAllActions...)))
+	return s.checking_a_personal_payee_named(name)
+}
+
+func (s *confirmationOfPayeeStage) checking_a_payee_with_body(body string) *confirmationOfPayeeStage {
+	request, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("http://localhost:%d/v1/organisation/accounts/confirmation-of-payee", ServerPort), strings.NewReader(body))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := authorisedHTTPClient(newTestToken(s.organisationId, accountPermissions(AuthoriseAllActions...))).Do(request)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	defer response.Body.Close()
+	s.statusCode = response.StatusCode
+	return s
+}
+
+func (s *confirmationOfPayeeStage) no_error_is_returned() *confirmationOfPayeeStage {
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *confirmationOfPayeeStage) the_result_is(result accountapi.PayeeResult, reasonCode string) *confirmationOfPayeeStage {
+	assert.Equal(s.t, result, s.confirmation.Result)
+	assert.Equal(s.t, reasonCode, s.confirmation.ReasonCode)
+	return s
+}
+
+func (s *confirmationOfPayeeStage) the_actual_name_is(name string) *confirmationOfPayeeStage {
+	assert.Equal(s.t, name, s.confirmation.ActualName)
+	return s
+}
+
+func (s *confirmationOfPayeeStage) the_status_code_is(statusCode int) *confirmationOfPayeeStage {
+	assert.Equal(s.t, statusCode, s.statusCode)
+	return s
+}
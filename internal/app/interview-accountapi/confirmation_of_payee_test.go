@@ -0,0 +1,188 @@
+package interview_accountapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_ConfirmationOfPayee_Match(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder")
+
+	when.
+		checking_a_personal_payee_named("Ms Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultMatch, "").and().
+		the_actual_name_is("")
+}
+
+func TestAcc_ConfirmationOfPayee_MatchOnAlternativeName(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder", "Sam Holder")
+
+	when.
+		checking_a_personal_payee_named("Sam Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultMatch, "")
+}
+
+func TestAcc_ConfirmationOfPayee_MatchOnJointAccountHolder(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_joint_account_named("Samantha Holder & Barry White")
+
+	when.
+		checking_a_personal_payee_named("Barry White")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultMatch, "")
+}
+
+func TestAcc_ConfirmationOfPayee_CloseMatchGivesActualName(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder")
+
+	when.
+		checking_a_personal_payee_named("Samanta Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultCloseMatch, "MBAM").and().
+		the_actual_name_is("Samantha Holder")
+}
+
+func TestAcc_ConfirmationOfPayee_BusinessAccountGivenAsPersonal(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_business_account_named("Holder Trading Ltd")
+
+	when.
+		checking_a_personal_payee_named("Holder Trading Limited")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultCloseMatch, "BANM").and().
+		the_actual_name_is("Holder Trading Ltd")
+}
+
+func TestAcc_ConfirmationOfPayee_PersonalAccountGivenAsBusiness(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder")
+
+	when.
+		checking_a_business_payee_named("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultCloseMatch, "PANM")
+}
+
+func TestAcc_ConfirmationOfPayee_NoMatch(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder")
+
+	when.
+		checking_a_personal_payee_named("Barry White")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultNoMatch, "ANNM").and().
+		the_actual_name_is("")
+}
+
+func TestAcc_ConfirmationOfPayee_AccountNotFound(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder")
+
+	when.
+		checking_another_account_number_for("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultAccountNotFound, "AC01")
+}
+
+func TestAcc_ConfirmationOfPayee_OptedOut(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		an_account_opted_out_of_matching_named("Samantha Holder")
+
+	when.
+		checking_a_personal_payee_named("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultOptedOut, "OPTO")
+}
+
+func TestAcc_ConfirmationOfPayee_AccountsOfOtherOrganisationsAreNotFound(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder")
+
+	when.
+		another_organisation_is_checking_a_personal_payee_named("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultAccountNotFound, "AC01")
+}
+
+func TestAcc_ConfirmationOfPayee_AccountHeldByTwoOrganisationsIsNoMatch(t *testing.T) {
+	given, when, then := ConfirmationOfPayeeTest(t)
+
+	given.
+		a_personal_account_named("Samantha Holder").and().
+		another_organisation_holds_the_account_number_for("Barry White")
+
+	when.
+		both_organisations_are_checking_a_personal_payee_named("Samantha Holder")
+
+	then.
+		no_error_is_returned().and().
+		the_result_is(accountapi.PayeeResultNoMatch, "ACNS").and().
+		the_actual_name_is("")
+}
+
+func TestAcc_ConfirmationOfPayee_InvalidRequest(t *testing.T) {
+	_, when, then := ConfirmationOfPayeeTest(t)
+
+	when.
+		checking_a_payee_with_body(`{"data": {"bank_id": "400300", "account_number": "71268996", "account_type": "joint", "name": "Samantha Holder"}}`)
+
+	then.
+		the_status_code_is(http.StatusBadRequest)
+}
+
+func TestAcc_ConfirmationOfPayee_MissingName(t *testing.T) {
+	_, when, then := ConfirmationOfPayeeTest(t)
+
+	when.
+		checking_a_payee_with_body(`{"data": {"bank_id": "400300", "account_number": "71268996", "account_type": "personal"}}`)
+
+	then.
+		the_status_code_is(http.StatusBadRequest)
+}
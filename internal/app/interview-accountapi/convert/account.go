@@ -1,10 +1,12 @@
 package convert
 
 import (
+	"bytes"
+	"encoding/json"
+
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/internalmodels"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
 	"github.com/go-openapi/strfmt"
-
 )
 
 func ToAccountDataRecord(item *models.AccountCreation) (*internalmodels.AccountRecord, error) {
@@ -19,33 +21,71 @@ func ToAccountDataRecord(item *models.AccountCreation) (*internalmodels.AccountR
 	return &internalmodels.AccountRecord{
 		ID:             id,
 		OrganisationID: organisationId,
-		Record: internalmodels.Account{
-			AccountClassification: item.Data.Attributes.AccountClassification,
-			AccountMatchingOptOut: item.Data.Attributes.AccountMatchingOptOut,
-			AccountNumber: item.Data.Attributes.AccountNumber,
-			AlternativeBankAccountNames: item.Data.Attributes.AlternativeBankAccountNames,
-			BankAccountName: item.Data.Attributes.BankAccountName,
-			BankID: item.Data.Attributes.BankID,
-			BankIDCode: item.Data.Attributes.BankIDCode,
-			BaseCurrency: item.Data.Attributes.BaseCurrency,
-			Bic: item.Data.Attributes.Bic,
-			Country: item.Data.Attributes.Country,
-			CustomerID: item.Data.Attributes.CustomerID,
-			FirstName: item.Data.Attributes.FirstName,
-			Iban: item.Data.Attributes.Iban,
-			JointAccount: item.Data.Attributes.JointAccount,
-			SecondaryIdentification: item.Data.Attributes.SecondaryIdentification,
-			Title: item.Data.Attributes.Title,
-		},
+		Record:         ToAccount(item.Data.Attributes),
 	}, nil
 }
 
+func ToAccount(attributes *models.AccountAttributes) internalmodels.Account {
+	return internalmodels.Account{
+		AccountClassification:       attributes.AccountClassification,
+		AccountMatchingOptOut:       attributes.AccountMatchingOptOut,
+		AccountNumber:               attributes.AccountNumber,
+		AlternativeBankAccountNames: attributes.AlternativeBankAccountNames,
+		BankAccountName:             attributes.BankAccountName,
+		BankID:                      attributes.BankID,
+		BankIDCode:                  attributes.BankIDCode,
+		BaseCurrency:                attributes.BaseCurrency,
+		Bic:                         attributes.Bic,
+		Country:                     attributes.Country,
+		CustomerID:                  attributes.CustomerID,
+		FirstName:                   attributes.FirstName,
+		Iban:                        attributes.Iban,
+		JointAccount:                attributes.JointAccount,
+		SecondaryIdentification:     attributes.SecondaryIdentification,
+		Title:                       attributes.Title,
+	}
+}
+
+// MergeAccountAttributes applies a partial attributes object on top of a stored account.
+// Attributes set to null are cleared, attributes that are not mentioned are left untouched.
+func MergeAccountAttributes(account internalmodels.Account, patch map[string]json.RawMessage) (*models.AccountAttributes, error) {
+	current, err := json.Marshal(account)
+	if err != nil {
+		return nil, err
+	}
+	merged := map[string]json.RawMessage{}
+	if err := json.Unmarshal(current, &merged); err != nil {
+		return nil, err
+	}
+	for name, value := range patch {
+		if string(value) == "null" {
+			delete(merged, name)
+		} else {
+			merged[name] = value
+		}
+	}
+	payload, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := &models.AccountAttributes{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
 func FromAccountDataRecord(record *internalmodels.AccountRecord) *models.Account {
 	return &models.Account{
 		ID:             FromUUID(record.ID),
 		OrganisationID: FromUUID(record.OrganisationID),
 		Type:           models.ResourceTypeAccounts,
 		Version:        record.Version,
+		Deleted:        record.IsDeleted,
+		Locked:         record.IsLocked,
 		ModifiedOn:     strfmt.DateTime(record.ModifiedOn),
 		CreatedOn:      strfmt.DateTime(record.CreatedOn),
 		Attributes: &models.AccountAttributes{
@@ -0,0 +1,162 @@
+package interview_accountapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/form3tech/go-security/security"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type deleteAccountStage struct {
+	t               *testing.T
+	client          *accountapi.Client
+	organisationId  string
+	token           string
+	createdAccounts []accountapi.Account
+	listedAccounts  *accountapi.AccountListData
+	response        *http.Response
+	fetchedAccount  *accountapi.AccountData
+	error           error
+}
+
+func DeleteAccountTest(t *testing.T) (*deleteAccountStage, *deleteAccountStage, *deleteAccountStage) {
+	organisationId := uuid.New()
+	token := newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))
+	stage := &deleteAccountStage{
+		t:              t,
+		client:         newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort), token),
+		organisationId: organisationId.String(),
+		token:          token,
+	}
+	return stage, stage, stage
+}
+
+func (s *deleteAccountStage) and() *deleteAccountStage {
+	return s
+}
+
+// an_administrator makes the requests that follow as a user who may also see deleted accounts.
+func (s *deleteAccountStage) an_administrator() *deleteAccountStage {
+	s.token = newTestToken(uuid.MustParse(s.organisationId),
+		accountPermissions(AuthoriseAllActions...),
+		security.Permission{RecordType: settings.AccountsAdminRecordType, AuthorisedActions: []security.AuthoriseAction{security.READ}},
+	)
+	s.client = newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort), s.token)
+	return s
+}
+
+func (s *deleteAccountStage) accounts_for_the_organisation(count int) *deleteAccountStage {
+	for i := 0; i < count; i++ {
+		created, err := s.client.Create(context.Background(), newTestAccount(s.organisationId, fmt.Sprintf("2000000%d", i), "400300"))
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+		s.createdAccounts = append(s.createdAccounts, *created)
+	}
+	return s
+}
+
+func (s *deleteAccountStage) the_first_account_was_deleted() *deleteAccountStage {
+	err := s.client.Delete(context.Background(), s.createdAccounts[0].ID, 0)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *deleteAccountStage) listing_the_accounts_of_the_organisation(includeDeleted bool) *deleteAccountStage {
+	s.listedAccounts, s.error = s.client.List(context.Background(), accountapi.ListOptions{
+		OrganisationIDs: []string{s.organisationId},
+		IncludeDeleted:  includeDeleted,
+	})
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *deleteAccountStage) fetching_the_deleted_account_with_include_deleted(value string) *deleteAccountStage {
+	url := fmt.Sprintf("http://localhost:%d/v1/organisation/accounts/%s?include_deleted=%s", ServerPort, s.createdAccounts[0].ID, value)
+	response, err := authorisedHTTPClient(s.token).Get(url)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	defer response.Body.Close()
+	s.response = response
+	if response.StatusCode == http.StatusOK {
+		s.fetchedAccount = &accountapi.AccountData{}
+		assert.NoError(s.t, json.NewDecoder(response.Body).Decode(s.fetchedAccount))
+	}
+	return s
+}
+
+func (s *deleteAccountStage) deleting_the_first_account_at_version(version int) *deleteAccountStage {
+	s.error = s.client.Delete(context.Background(), s.createdAccounts[0].ID, version)
+	return s
+}
+
+func (s *deleteAccountStage) updating_the_deleted_account_at_version(version int) *deleteAccountStage {
+	_, s.error = s.client.Update(context.Background(), s.createdAccounts[0].ID, version, map[string]interface{}{
+		"bank_account_name": "Samantha Baker",
+	})
+	return s
+}
+
+func (s *deleteAccountStage) no_error_is_returned() *deleteAccountStage {
+	assert.NoError(s.t, s.error)
+	return s
+}
+
+func (s *deleteAccountStage) the_error_is(expected error) *deleteAccountStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+	return s
+}
+
+func (s *deleteAccountStage) the_status_code_is(statusCode int) *deleteAccountStage {
+	assert.Equal(s.t, statusCode, s.response.StatusCode)
+	return s
+}
+
+func (s *deleteAccountStage) the_listed_accounts_are(count int) *deleteAccountStage {
+	assert.Len(s.t, s.listedAccounts.Data, count)
+	return s
+}
+
+func (s *deleteAccountStage) the_deleted_account_is_not_listed() *deleteAccountStage {
+	for _, account := range s.listedAccounts.Data {
+		assert.NotEqual(s.t, s.createdAccounts[0].ID, account.ID)
+		assert.False(s.t, account.Deleted)
+	}
+	return s
+}
+
+func (s *deleteAccountStage) the_deleted_account_is_listed_at_version(version int) *deleteAccountStage {
+	for _, account := range s.listedAccounts.Data {
+		if account.ID == s.createdAccounts[0].ID {
+			assert.True(s.t, account.Deleted)
+			assert.Equal(s.t, version, account.Version)
+			return s
+		}
+	}
+	assert.Fail(s.t, "deleted account is not listed")
+	return s
+}
+
+func (s *deleteAccountStage) the_fetched_account_is_deleted_at_version(version int) *deleteAccountStage {
+	if !assert.NotNil(s.t, s.fetchedAccount) {
+		return s
+	}
+	assert.Equal(s.t, s.createdAccounts[0].ID, s.fetchedAccount.Data.ID)
+	assert.True(s.t, s.fetchedAccount.Data.Deleted)
+	assert.Equal(s.t, version, s.fetchedAccount.Data.Version)
+	assert.Equal(s.t, s.createdAccounts[0].Attributes, s.fetchedAccount.Data.Attributes)
+	return s
+}
@@ -0,0 +1,112 @@
+package interview_accountapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_DeleteAccount_HiddenFromList(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(2).and().
+		the_first_account_was_deleted()
+
+	when.
+		listing_the_accounts_of_the_organisation(false)
+
+	then.
+		the_listed_accounts_are(1).and().
+		the_deleted_account_is_not_listed()
+}
+
+func TestAcc_DeleteAccount_ListedWhenIncludingDeleted(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		an_administrator().and().
+		accounts_for_the_organisation(2).and().
+		the_first_account_was_deleted()
+
+	when.
+		listing_the_accounts_of_the_organisation(true)
+
+	then.
+		the_listed_accounts_are(2).and().
+		the_deleted_account_is_listed_at_version(1)
+}
+
+func TestAcc_DeleteAccount_FetchedWhenIncludingDeleted(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		an_administrator().and().
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_deleted()
+
+	when.
+		fetching_the_deleted_account_with_include_deleted("true")
+
+	then.
+		the_status_code_is(http.StatusOK).and().
+		the_fetched_account_is_deleted_at_version(1)
+}
+
+func TestAcc_DeleteAccount_InvalidIncludeDeleted(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		an_administrator().and().
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_deleted()
+
+	when.
+		fetching_the_deleted_account_with_include_deleted("sometimes")
+
+	then.
+		the_status_code_is(http.StatusBadRequest)
+}
+
+func TestAcc_DeleteAccount_DeletingTwiceSucceeds(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_deleted()
+
+	when.
+		deleting_the_first_account_at_version(0)
+
+	then.
+		no_error_is_returned()
+}
+
+func TestAcc_DeleteAccount_CannotBeUpdated(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_deleted()
+
+	when.
+		updating_the_deleted_account_at_version(1)
+
+	then.
+		the_error_is(accountapi.ErrNotFound)
+}
+
+func TestAcc_DeleteAccount_IncludeDeletedNeedsAdministrator(t *testing.T) {
+	given, when, then := DeleteAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_deleted()
+
+	when.
+		fetching_the_deleted_account_with_include_deleted("true")
+
+	then.
+		the_status_code_is(http.StatusForbidden)
+}
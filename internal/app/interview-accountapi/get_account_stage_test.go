@@ -40,7 +40,7 @@ func (s *getAccountStage) and() *getAccountStage {
 }
 
 func (s *getAccountStage) an_authorized_service_user() *getAccountStage {
-	s.client = NewAccountAPIClient(ServerPort)
+	s.client = NewAccountAPIClient(ServerPort, newTestToken(s.organisationId, accountPermissions(AuthoriseAllActions...)))
 	return s
 }
 
@@ -0,0 +1,92 @@
+package interview_accountapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/swagger-client/interview-accountapi/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type listAccountsStage struct {
+	t              *testing.T
+	organisationId uuid.UUID
+	token          string
+	statusCode     int
+	body           []byte
+}
+
+func ListAccountsTest(t *testing.T) (*listAccountsStage, *listAccountsStage, *listAccountsStage) {
+	organisationId := uuid.New()
+	stage := &listAccountsStage{
+		t:              t,
+		organisationId: organisationId,
+		token:          newTestToken(organisationId, accountPermissions(AuthoriseAllActions...)),
+	}
+	return stage, stage, stage
+}
+
+func (s *listAccountsStage) and() *listAccountsStage {
+	return s
+}
+
+func (s *listAccountsStage) accounts_for_the_organisation(count int) *listAccountsStage {
+	client := newAccountClient(s.t, fmt.Sprintf("http://localhost:%d", ServerPort), s.token)
+	for i := 0; i < count; i++ {
+		_, err := client.Create(context.Background(), newTestAccount(s.organisationId.String(), fmt.Sprintf("3000000%d", i), "400300"))
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+	}
+	return s
+}
+
+func (s *listAccountsStage) listing_the_accounts_with_page(number string, size string) *listAccountsStage {
+	query := url.Values{}
+	query.Set("filter[organisation_id]", s.organisationId.String())
+	if number != "" {
+		query.Set("page[number]", number)
+	}
+	if size != "" {
+		query.Set("page[size]", size)
+	}
+	resp, err := authorisedHTTPClient(s.token).Get(fmt.Sprintf("http://localhost:%d/v1/organisation/accounts?%s", ServerPort, query.Encode()))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	defer resp.Body.Close()
+	s.statusCode = resp.StatusCode
+	s.body, err = ioutil.ReadAll(resp.Body)
+	assert.NoError(s.t, err)
+	return s
+}
+
+func (s *listAccountsStage) the_response_is_ok_with_accounts(count int) *listAccountsStage {
+	if !assert.Equal(s.t, http.StatusOK, s.statusCode, string(s.body)) {
+		return s
+	}
+	response := models.AccountDetailsListResponse{}
+	if !assert.NoError(s.t, json.Unmarshal(s.body, &response)) {
+		return s
+	}
+	assert.Len(s.t, response.Data, count)
+	return s
+}
+
+func (s *listAccountsStage) the_response_is_bad_request_about(parameter string) *listAccountsStage {
+	if !assert.Equal(s.t, http.StatusBadRequest, s.statusCode, string(s.body)) {
+		return s
+	}
+	apiError := models.APIError{}
+	if !assert.NoError(s.t, json.Unmarshal(s.body, &apiError)) {
+		return s
+	}
+	assert.Contains(s.t, apiError.ErrorMessage, parameter)
+	return s
+}
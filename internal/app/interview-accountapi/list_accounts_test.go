@@ -0,0 +1,72 @@
+package interview_accountapi
+
+import (
+	"testing"
+)
+
+func TestAcc_ListAccounts_WithPageNumberAndSize(t *testing.T) {
+	given, when, then := ListAccountsTest(t)
+
+	given.
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_with_page("1", "2")
+
+	then.
+		the_response_is_ok_with_accounts(1)
+}
+
+func TestAcc_ListAccounts_LastPage(t *testing.T) {
+	given, when, then := ListAccountsTest(t)
+
+	given.
+		accounts_for_the_organisation(3)
+
+	when.
+		listing_the_accounts_with_page("last", "2")
+
+	then.
+		the_response_is_ok_with_accounts(1)
+}
+
+func TestAcc_ListAccounts_FirstPageOfNoAccounts(t *testing.T) {
+	_, when, then := ListAccountsTest(t)
+
+	when.
+		listing_the_accounts_with_page("0", "")
+
+	then.
+		the_response_is_ok_with_accounts(0)
+}
+
+func TestAcc_ListAccounts_InvalidPageParameters(t *testing.T) {
+	tests := []struct {
+		name      string
+		number    string
+		size      string
+		parameter string
+	}{
+		{name: "non-numeric size", size: "ten", parameter: "page[size]"},
+		{name: "zero size", size: "0", parameter: "page[size]"},
+		{name: "negative size", size: "-5", parameter: "page[size]"},
+		{name: "size above maximum", size: "1001", parameter: "page[size]"},
+		{name: "non-numeric number", number: "second", parameter: "page[number]"},
+		{name: "negative number", number: "-1", parameter: "page[number]"},
+		{name: "number beyond last page", number: "2", size: "2", parameter: "page[number]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			given, when, then := ListAccountsTest(t)
+
+			given.
+				accounts_for_the_organisation(3)
+
+			when.
+				listing_the_accounts_with_page(tt.number, tt.size)
+
+			then.
+				the_response_is_bad_request_about(tt.parameter)
+		})
+	}
+}
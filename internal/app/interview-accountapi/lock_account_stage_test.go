@@ -0,0 +1,125 @@
+package interview_accountapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type lockAccountStage struct {
+	t               *testing.T
+	client          *accountapi.Client
+	organisationId  string
+	createdAccounts []accountapi.Account
+	listedAccounts  *accountapi.AccountListData
+	error           error
+}
+
+func LockAccountTest(t *testing.T) (*lockAccountStage, *lockAccountStage, *lockAccountStage) {
+	organisationId := uuid.New()
+	token := newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))
+	stage := &lockAccountStage{
+		t:              t,
+		client:         newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort), token),
+		organisationId: organisationId.String(),
+	}
+	return stage, stage, stage
+}
+
+func (s *lockAccountStage) and() *lockAccountStage {
+	return s
+}
+
+func (s *lockAccountStage) accounts_for_the_organisation(count int) *lockAccountStage {
+	for i := 0; i < count; i++ {
+		created, err := s.client.Create(context.Background(), newTestAccount(s.organisationId, fmt.Sprintf("3000000%d", i), "400300"))
+		if !assert.NoError(s.t, err) {
+			s.t.FailNow()
+		}
+		s.createdAccounts = append(s.createdAccounts, *created)
+	}
+	return s
+}
+
+func (s *lockAccountStage) the_first_account_was_locked() *lockAccountStage {
+	_, err := s.client.Lock(context.Background(), s.createdAccounts[0].ID, 0)
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *lockAccountStage) locking_the_first_account_at_version(version int) *lockAccountStage {
+	_, s.error = s.client.Lock(context.Background(), s.createdAccounts[0].ID, version)
+	return s
+}
+
+func (s *lockAccountStage) unlocking_the_first_account_at_version(version int) *lockAccountStage {
+	_, s.error = s.client.Unlock(context.Background(), s.createdAccounts[0].ID, version)
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *lockAccountStage) updating_the_first_account_at_version(version int) *lockAccountStage {
+	_, s.error = s.client.Update(context.Background(), s.createdAccounts[0].ID, version, map[string]interface{}{
+		"bank_account_name": "Samantha Baker",
+	})
+	return s
+}
+
+func (s *lockAccountStage) deleting_the_first_account_at_version(version int) *lockAccountStage {
+	s.error = s.client.Delete(context.Background(), s.createdAccounts[0].ID, version)
+	return s
+}
+
+func (s *lockAccountStage) listing_the_accounts_of_the_organisation_that_are_locked(locked bool) *lockAccountStage {
+	s.listedAccounts, s.error = s.client.List(context.Background(), accountapi.ListOptions{
+		OrganisationIDs: []string{s.organisationId},
+		Locked:          &locked,
+	})
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *lockAccountStage) no_error_is_returned() *lockAccountStage {
+	assert.NoError(s.t, s.error)
+	return s
+}
+
+func (s *lockAccountStage) the_error_is(expected error) *lockAccountStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+	return s
+}
+
+func (s *lockAccountStage) the_first_account_is_locked_at_version(locked bool, version int) *lockAccountStage {
+	stored, err := s.client.Fetch(context.Background(), s.createdAccounts[0].ID)
+	if !assert.NoError(s.t, err) {
+		return s
+	}
+	assert.Equal(s.t, locked, stored.Locked)
+	assert.Equal(s.t, version, stored.Version)
+	return s
+}
+
+func (s *lockAccountStage) only_the_first_account_is_listed() *lockAccountStage {
+	if !assert.Len(s.t, s.listedAccounts.Data, 1) {
+		return s
+	}
+	assert.Equal(s.t, s.createdAccounts[0].ID, s.listedAccounts.Data[0].ID)
+	assert.True(s.t, s.listedAccounts.Data[0].Locked)
+	return s
+}
+
+func (s *lockAccountStage) all_but_the_first_account_are_listed() *lockAccountStage {
+	assert.Equal(s.t, s.createdAccounts[1:], s.listedAccounts.Data)
+	return s
+}
@@ -0,0 +1,121 @@
+package interview_accountapi
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_LockAccount(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1)
+
+	when.
+		locking_the_first_account_at_version(0)
+
+	then.
+		no_error_is_returned().and().
+		the_first_account_is_locked_at_version(true, 1)
+}
+
+func TestAcc_LockAccount_LockingTwiceSucceeds(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_locked()
+
+	when.
+		locking_the_first_account_at_version(0)
+
+	then.
+		no_error_is_returned().and().
+		the_first_account_is_locked_at_version(true, 1)
+}
+
+func TestAcc_LockAccount_StaleVersion(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1)
+
+	when.
+		locking_the_first_account_at_version(3)
+
+	then.
+		the_error_is(accountapi.ErrConflict)
+}
+
+func TestAcc_LockAccount_LockedAccountCannotBeUpdated(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_locked()
+
+	when.
+		updating_the_first_account_at_version(1)
+
+	then.
+		the_error_is(accountapi.ErrLocked)
+}
+
+func TestAcc_LockAccount_LockedAccountCannotBeDeleted(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_locked()
+
+	when.
+		deleting_the_first_account_at_version(1)
+
+	then.
+		the_error_is(accountapi.ErrLocked)
+}
+
+func TestAcc_LockAccount_UnlockedAccountCanBeUpdated(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(1).and().
+		the_first_account_was_locked()
+
+	when.
+		unlocking_the_first_account_at_version(1).and().
+		updating_the_first_account_at_version(2)
+
+	then.
+		no_error_is_returned().and().
+		the_first_account_is_locked_at_version(false, 3)
+}
+
+func TestAcc_LockAccount_ListFilteredOnLockState(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(3).and().
+		the_first_account_was_locked()
+
+	when.
+		listing_the_accounts_of_the_organisation_that_are_locked(true)
+
+	then.
+		only_the_first_account_is_listed()
+}
+
+func TestAcc_LockAccount_ListFilteredOnUnlocked(t *testing.T) {
+	given, when, then := LockAccountTest(t)
+
+	given.
+		accounts_for_the_organisation(3).and().
+		the_first_account_was_locked()
+
+	when.
+		listing_the_accounts_of_the_organisation_that_are_locked(false)
+
+	then.
+		all_but_the_first_account_are_listed()
+}
@@ -2,14 +2,23 @@ package interview_accountapi
 
 import (
 	"fmt"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/eventhandlers"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/eventsinks"
 	"github.com/form3tech-oss/interview-accountapi-pair-programming/internal/app/interview-accountapi/api/settings"
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
 	"github.com/form3tech/go-security/security"
 	"github.com/google/uuid"
 	"github.com/phayes/freeport"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -30,9 +39,83 @@ func getServerPort() int {
 	return serverPort
 }
 
+// aclServer serves the ACLs that the acl_url claim of the test tokens points to.
+var aclServer *httptest.Server
+var testAcls sync.Map
+
+// newTestToken signs a token for testUserId, granting the given permissions on the organisation.
+func newTestToken(organisationId uuid.UUID, permissions ...security.Permission) string {
+	return newTestTokenForOrganisations([]uuid.UUID{organisationId}, permissions...)
+}
+
+// newTestTokenForOrganisations signs a token for testUserId, granting the given permissions on each
+// of the organisations.
+func newTestTokenForOrganisations(organisationIds []uuid.UUID, permissions ...security.Permission) string {
+	acls, err := security.EncodeAcls(organisationIds, permissions)
+	if err != nil {
+		panic(err)
+	}
+	aclId := uuid.New().String()
+	testAcls.Store(aclId, acls)
+
+	token, err := security.NewJwtToken(testKeyPair.RsaPrivateKey, testUserId).
+		ForOrganisations(organisationIds...).
+		WithAclUrl(fmt.Sprintf("%s/%s", aclServer.URL, aclId)).
+		Build()
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+func accountPermissions(actions ...security.AuthoriseAction) security.Permission {
+	return security.Permission{
+		RecordType:        settings.AccountsRecordType,
+		AuthorisedActions: actions,
+	}
+}
+
+func authorisedHTTPClient(token string) *http.Client {
+	return &http.Client{Transport: &transport{
+		token:               token,
+		underlyingTransport: http.DefaultTransport,
+	}}
+}
+
+// newAccountClient returns a client of the account API served at baseURL, which sends the token.
+func newAccountClient(t *testing.T, baseURL string, token string) *accountapi.Client {
+	client, err := accountapi.NewClient(baseURL)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return client.WithHTTPClient(authorisedHTTPClient(token))
+}
+
 func TestMain(m *testing.M) {
 	_ = os.Setenv("STACK_NAME", "local")
 
+	var err error
+	if testKeyPair, err = security.GenerateTestKeyPair(); err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("JWT_PUBLIC_KEY", testKeyPair.PublicKeyPem)
+	// the events are read from a channel sender installed once the server is configured, with the
+	// file sink selected until then
+	eventsDir, err := ioutil.TempDir("", "interview-accountapi-events")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("EVENT_SINK", eventsinks.File)
+	_ = os.Setenv("EVENT_SINK_FILE", filepath.Join(eventsDir, "events.jsonl"))
+
+	aclServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acls, ok := testAcls.Load(strings.TrimPrefix(r.URL.Path, "/"))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(acls.(string)))
+	}))
 
 	ServerPort = getServerPort()
 	settings.ServerPort = ServerPort
@@ -41,6 +124,10 @@ func TestMain(m *testing.M) {
 
 	Configure()
 
+	events := eventsinks.NewChannelSender(1000)
+	eventhandlers.SetForm3EventSender(events)
+	go recordDispatchedEvents(events)
+
 	startedSignal := make(chan bool)
 	stopServer := make(chan bool, 1)
 
@@ -50,6 +137,8 @@ func TestMain(m *testing.M) {
 	result := m.Run()
 
 	stopServer <- true
+	aclServer.Close()
+	_ = os.RemoveAll(eventsDir)
 
 	os.Exit(result)
 }
\ No newline at end of file
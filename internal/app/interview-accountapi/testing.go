@@ -20,7 +20,7 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return t.underlyingTransport.RoundTrip(req)
 }
 
-func NewAccountAPIClient(port int) *account_api.Client {
+func NewAccountAPIClient(port int, token string) *account_api.Client {
 	config := client.DefaultTransportConfig().
 		WithHost(fmt.Sprintf("%s:%d", "localhost", port)).
 		WithSchemes([]string{"http"})
@@ -28,6 +28,7 @@ func NewAccountAPIClient(port int) *account_api.Client {
 	config.WithBasePath("/v1")
 
 	transport := &transport{
+		token:               token,
 		underlyingTransport: http.DefaultTransport,
 	}
 	h := &http.Client{Transport: transport}
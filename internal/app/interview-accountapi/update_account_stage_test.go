@@ -0,0 +1,135 @@
+package interview_accountapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type updateAccountStage struct {
+	t              *testing.T
+	client         *accountapi.Client
+	organisationId uuid.UUID
+	account        accountapi.Account
+	otherAccount   accountapi.Account
+	updatedAccount *accountapi.Account
+	error          error
+}
+
+func UpdateAccountTest(t *testing.T) (*updateAccountStage, *updateAccountStage, *updateAccountStage) {
+	organisationId := uuid.New()
+	token := newTestToken(organisationId, accountPermissions(AuthoriseAllActions...))
+	stage := &updateAccountStage{
+		t:              t,
+		client:         newAccountClient(t, fmt.Sprintf("http://localhost:%d", ServerPort), token),
+		organisationId: organisationId,
+	}
+	return stage, stage, stage
+}
+
+func (s *updateAccountStage) and() *updateAccountStage {
+	return s
+}
+
+func (s *updateAccountStage) an_existing_account() *updateAccountStage {
+	created, err := s.client.Create(context.Background(), newTestAccount(s.organisationId.String(), "41426819", "400300"))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.account = *created
+	return s
+}
+
+func (s *updateAccountStage) another_account_with_number(accountNumber string) *updateAccountStage {
+	created, err := s.client.Create(context.Background(), newTestAccount(s.organisationId.String(), accountNumber, "400300"))
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	s.otherAccount = *created
+	return s
+}
+
+func (s *updateAccountStage) a_non_existing_account() *updateAccountStage {
+	s.account = newTestAccount(s.organisationId.String(), "41426819", "400300")
+	return s
+}
+
+func (s *updateAccountStage) the_account_was_updated_at_version(version int) *updateAccountStage {
+	_, err := s.client.Update(context.Background(), s.account.ID, version, map[string]interface{}{
+		"bank_account_name": "Samantha Baker",
+	})
+	if !assert.NoError(s.t, err) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *updateAccountStage) updating_the_account_at_version(version int, attributes map[string]interface{}) *updateAccountStage {
+	s.updatedAccount, s.error = s.client.Update(context.Background(), s.account.ID, version, attributes)
+	return s
+}
+
+func (s *updateAccountStage) no_error_is_returned() *updateAccountStage {
+	if !assert.NoError(s.t, s.error) {
+		s.t.FailNow()
+	}
+	return s
+}
+
+func (s *updateAccountStage) the_account_is_at_version(version int) *updateAccountStage {
+	assert.Equal(s.t, version, s.updatedAccount.Version)
+	return s.the_stored_account_is_at_version(version)
+}
+
+func (s *updateAccountStage) the_stored_account_is_at_version(version int) *updateAccountStage {
+	stored, err := s.client.Fetch(context.Background(), s.account.ID)
+	if assert.NoError(s.t, err) {
+		assert.Equal(s.t, version, stored.Version)
+	}
+	return s
+}
+
+func (s *updateAccountStage) the_account_has_name_and_opt_out(name string, optOut bool) *updateAccountStage {
+	assert.Equal(s.t, name, s.updatedAccount.Attributes.BankAccountName)
+	assert.Equal(s.t, optOut, s.updatedAccount.Attributes.AccountMatchingOptOut)
+	return s
+}
+
+func (s *updateAccountStage) the_other_attributes_are_unchanged() *updateAccountStage {
+	expected := s.account.Attributes
+	actual := s.updatedAccount.Attributes
+	assert.Equal(s.t, expected.Country, actual.Country)
+	assert.Equal(s.t, expected.AccountNumber, actual.AccountNumber)
+	assert.Equal(s.t, expected.BankID, actual.BankID)
+	assert.Equal(s.t, expected.Bic, actual.Bic)
+	assert.Equal(s.t, s.account.OrganisationID, s.updatedAccount.OrganisationID)
+	return s
+}
+
+func (s *updateAccountStage) the_bic_is_cleared() *updateAccountStage {
+	assert.Empty(s.t, s.updatedAccount.Attributes.Bic)
+	assert.Equal(s.t, s.account.Attributes.BankID, s.updatedAccount.Attributes.BankID)
+	return s
+}
+
+func (s *updateAccountStage) the_account_has_iban(iban string) *updateAccountStage {
+	assert.Equal(s.t, iban, s.updatedAccount.Attributes.IBAN)
+	return s
+}
+
+func (s *updateAccountStage) the_error_is(expected error) *updateAccountStage {
+	assert.True(s.t, errors.Is(s.error, expected), "expected %v but got %v", expected, s.error)
+	return s
+}
+
+func (s *updateAccountStage) the_error_names_the_other_account() *updateAccountStage {
+	if assert.Error(s.t, s.error) {
+		assert.Contains(s.t, s.error.Error(), s.otherAccount.ID)
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package interview_accountapi
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/interview-accountapi-pair-programming/pkg/accountapi"
+)
+
+func TestAcc_UpdateAccount(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"bank_account_name":              "Samantha Baker",
+			"alternative_bank_account_names": []string{"Sam Baker"},
+			"account_matching_opt_out":       true,
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_account_is_at_version(1).and().
+		the_account_has_name_and_opt_out("Samantha Baker", true).and().
+		the_other_attributes_are_unchanged()
+}
+
+func TestAcc_UpdateAccount_ClearsNullAttributes(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"bic": nil,
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_account_is_at_version(1).and().
+		the_bic_is_cleared()
+}
+
+func TestAcc_UpdateAccount_TwiceInARow(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account().and().
+		the_account_was_updated_at_version(0)
+
+	when.
+		updating_the_account_at_version(1, map[string]interface{}{
+			"bank_account_name": "Sam Holder",
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_account_is_at_version(2)
+}
+
+func TestAcc_UpdateAccount_StaleVersion(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account().and().
+		the_account_was_updated_at_version(0)
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"bank_account_name": "Sam Holder",
+		})
+
+	then.
+		the_error_is(accountapi.ErrConflict).and().
+		the_stored_account_is_at_version(1)
+}
+
+func TestAcc_UpdateAccount_InvalidAttribute(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"country": "United Kingdom",
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation).and().
+		the_stored_account_is_at_version(0)
+}
+
+func TestAcc_UpdateAccount_InvalidIBAN(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"iban": "GB11NWBK40030041426819",
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation).and().
+		the_stored_account_is_at_version(0)
+}
+
+func TestAcc_UpdateAccount_CountryInconsistentWithBIC(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"country": "FR",
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation).and().
+		the_stored_account_is_at_version(0)
+}
+
+func TestAcc_UpdateAccount_BreakingTheRulesOfTheCountry(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"base_currency": "EUR",
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation).and().
+		the_stored_account_is_at_version(0)
+}
+
+func TestAcc_UpdateAccount_DerivesTheIBANAgain(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"account_number": "41426827",
+		})
+
+	then.
+		no_error_is_returned().and().
+		the_account_has_iban("GB91NWBK40030041426827")
+}
+
+func TestAcc_UpdateAccount_ToTheNumberOfAnother(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account().and().
+		another_account_with_number("41426820")
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"account_number": "41426820",
+		})
+
+	then.
+		the_error_is(accountapi.ErrConflict).and().
+		the_error_names_the_other_account().and().
+		the_stored_account_is_at_version(0)
+}
+
+func TestAcc_UpdateAccount_UnknownAttribute(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		an_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"nickname": "Sam",
+		})
+
+	then.
+		the_error_is(accountapi.ErrValidation)
+}
+
+func TestAcc_UpdateAccount_NotFound(t *testing.T) {
+	given, when, then := UpdateAccountTest(t)
+
+	given.
+		a_non_existing_account()
+
+	when.
+		updating_the_account_at_version(0, map[string]interface{}{
+			"bank_account_name": "Sam Holder",
+		})
+
+	then.
+		the_error_is(accountapi.ErrNotFound)
+}
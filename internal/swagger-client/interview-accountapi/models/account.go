@@ -25,10 +25,18 @@ type Account struct {
 	// Format: date-time
 	CreatedOn strfmt.DateTime `json:"created_on,omitempty"`
 
+	// deleted
+	// Read Only: true
+	Deleted bool `json:"deleted,omitempty"`
+
 	// id
 	// Format: uuid
 	ID strfmt.UUID `json:"id,omitempty"`
 
+	// locked
+	// Read Only: true
+	Locked bool `json:"locked,omitempty"`
+
 	// modified on
 	// Read Only: true
 	// Format: date-time
@@ -0,0 +1,133 @@
+/* #nosec */// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"encoding/json"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// AccountBulkCreationResult account bulk creation result
+// swagger:model AccountBulkCreationResult
+type AccountBulkCreationResult struct {
+
+	// Id of the account, as given in the request
+	// Format: uuid
+	ID strfmt.UUID `json:"id,omitempty"`
+
+	// Why the account was not created
+	Reason string `json:"reason,omitempty"`
+
+	// What became of the account. Accounts of an atomic request that could have been created are rolled_back when another one could not.
+	// Enum: [created duplicate invalid forbidden rolled_back]
+	Status string `json:"status,omitempty"`
+}
+
+// Validate validates this account bulk creation result
+func (m *AccountBulkCreationResult) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateID(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateStatus(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *AccountBulkCreationResult) validateID(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ID) { // not required
+		return nil
+	}
+
+	if err := validate.FormatOf("id", "body", "uuid", m.ID.String(), formats); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var accountBulkCreationResultTypeStatusPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["created","duplicate","invalid","forbidden","rolled_back"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		accountBulkCreationResultTypeStatusPropEnum = append(accountBulkCreationResultTypeStatusPropEnum, v)
+	}
+}
+
+const (
+
+	// AccountBulkCreationResultStatusCreated captures enum value "created"
+	AccountBulkCreationResultStatusCreated string = "created"
+
+	// AccountBulkCreationResultStatusDuplicate captures enum value "duplicate"
+	AccountBulkCreationResultStatusDuplicate string = "duplicate"
+
+	// AccountBulkCreationResultStatusInvalid captures enum value "invalid"
+	AccountBulkCreationResultStatusInvalid string = "invalid"
+
+	// AccountBulkCreationResultStatusForbidden captures enum value "forbidden"
+	AccountBulkCreationResultStatusForbidden string = "forbidden"
+
+	// AccountBulkCreationResultStatusRolledBack captures enum value "rolled_back"
+	AccountBulkCreationResultStatusRolledBack string = "rolled_back"
+)
+
+// prop value enum
+func (m *AccountBulkCreationResult) validateStatusEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, accountBulkCreationResultTypeStatusPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *AccountBulkCreationResult) validateStatus(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Status) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateStatusEnum("status", "body", m.Status); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *AccountBulkCreationResult) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *AccountBulkCreationResult) UnmarshalBinary(b []byte) error {
+	var res AccountBulkCreationResult
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,193 @@
+/* #nosec */// Code generated by go-swagger; DO NOT EDIT.
+
+package models
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"encoding/json"
+
+	strfmt "github.com/go-openapi/strfmt"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// ConfirmationOfPayeeCheck confirmation of payee check
+// swagger:model ConfirmationOfPayeeCheck
+type ConfirmationOfPayeeCheck struct {
+
+	// Account number of the payee
+	// Required: true
+	// Pattern: ^[A-Z0-9]{0,64}$
+	AccountNumber *string `json:"account_number"`
+
+	// Whether the payer expects a personal or a business account
+	// Required: true
+	// Enum: [personal business]
+	AccountType *string `json:"account_type"`
+
+	// Local country bank identifier. In the UK this is the sort code.
+	// Required: true
+	// Pattern: ^[A-Z0-9]{0,16}$
+	BankID *string `json:"bank_id"`
+
+	// Name of the payee as given by the payer
+	// Required: true
+	// Max Length: 140
+	// Min Length: 1
+	Name *string `json:"name"`
+
+	// Secondary identification, e.g. building society roll number
+	// Max Length: 140
+	SecondaryIdentification string `json:"secondary_identification,omitempty"`
+}
+
+// Validate validates this confirmation of payee check
+func (m *ConfirmationOfPayeeCheck) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateAccountNumber(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateAccountType(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateBankID(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateName(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateSecondaryIdentification(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ConfirmationOfPayeeCheck) validateAccountNumber(formats strfmt.Registry) error {
+
+	if err := validate.Required("account_number", "body", m.AccountNumber); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("account_number", "body", string(*m.AccountNumber), `^[A-Z0-9]{0,64}$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var confirmationOfPayeeCheckTypeAccountTypePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["personal","business"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		confirmationOfPayeeCheckTypeAccountTypePropEnum = append(confirmationOfPayeeCheckTypeAccountTypePropEnum, v)
+	}
+}
+
+const (
+
+	// ConfirmationOfPayeeCheckAccountTypePersonal captures enum value "personal"
+	ConfirmationOfPayeeCheckAccountTypePersonal string = "personal"
+
+	// ConfirmationOfPayeeCheckAccountTypeBusiness captures enum value "business"
+	ConfirmationOfPayeeCheckAccountTypeBusiness string = "business"
+)
+
+// prop value enum
+func (m *ConfirmationOfPayeeCheck) validateAccountTypeEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, confirmationOfPayeeCheckTypeAccountTypePropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ConfirmationOfPayeeCheck) validateAccountType(formats strfmt.Registry) error {
+
+	if err := validate.Required("account_type", "body", m.AccountType); err != nil {
+		return err
+	}
+
+	// value enum
+	if err := m.validateAccountTypeEnum("account_type", "body", *m.AccountType); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ConfirmationOfPayeeCheck) validateBankID(formats strfmt.Registry) error {
+
+	if err := validate.Required("bank_id", "body", m.BankID); err != nil {
+		return err
+	}
+
+	if err := validate.Pattern("bank_id", "body", string(*m.BankID), `^[A-Z0-9]{0,16}$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ConfirmationOfPayeeCheck) validateName(formats strfmt.Registry) error {
+
+	if err := validate.Required("name", "body", m.Name); err != nil {
+		return err
+	}
+
+	if err := validate.MinLength("name", "body", string(*m.Name), 1); err != nil {
+		return err
+	}
+
+	if err := validate.MaxLength("name", "body", string(*m.Name), 140); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *ConfirmationOfPayeeCheck) validateSecondaryIdentification(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.SecondaryIdentification) { // not required
+		return nil
+	}
+
+	if err := validate.MaxLength("secondary_identification", "body", string(m.SecondaryIdentification), 140); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *ConfirmationOfPayeeCheck) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *ConfirmationOfPayeeCheck) UnmarshalBinary(b []byte) error {
+	var res ConfirmationOfPayeeCheck
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -1,5 +1,7 @@
 package accountapi
 
+const ResourceTypeAccounts = "accounts"
+
 type AccountClassification string
 
 const (
@@ -8,11 +10,112 @@ const (
 )
 
 type AccountListData struct {
-	Data []Account `json:"data"`
+	Data  []Account `json:"data"`
+	Links *Links    `json:"links,omitempty"`
 }
 
 type AccountData struct {
-	Data Account `json:"data"`
+	Data  Account `json:"data"`
+	Links *Links  `json:"links,omitempty"`
+}
+
+type MatchStrength string
+
+const (
+	MatchStrengthExact MatchStrength = "exact"
+	MatchStrengthClose MatchStrength = "close"
+	MatchStrengthNone  MatchStrength = "none"
+)
+
+type AccountMatch struct {
+	Account       Account       `json:"account"`
+	MatchStrength MatchStrength `json:"match_strength"`
+	MatchedName   string        `json:"matched_name,omitempty"`
+	Score         float64       `json:"score,omitempty"`
+}
+
+type AccountSearchData struct {
+	Data []AccountMatch `json:"data"`
+}
+
+type AccountType string
+
+const (
+	AccountTypePersonal AccountType = "personal"
+	AccountTypeBusiness AccountType = "business"
+)
+
+// PayeeCheck is what a payer knows of the account they are about to pay.
+type PayeeCheck struct {
+	BankID                  string      `json:"bank_id"`
+	AccountNumber           string      `json:"account_number"`
+	AccountType             AccountType `json:"account_type"`
+	Name                    string      `json:"name"`
+	SecondaryIdentification string      `json:"secondary_identification,omitempty"`
+}
+
+type PayeeResult string
+
+const (
+	PayeeResultMatch           PayeeResult = "match"
+	PayeeResultCloseMatch      PayeeResult = "close_match"
+	PayeeResultNoMatch         PayeeResult = "no_match"
+	PayeeResultAccountNotFound PayeeResult = "account_not_found"
+	PayeeResultOptedOut        PayeeResult = "opted_out"
+)
+
+type PayeeConfirmation struct {
+	Result     PayeeResult `json:"result"`
+	ReasonCode string      `json:"reason_code,omitempty"`
+	ActualName string      `json:"actual_name,omitempty"`
+}
+
+type BulkCreateStatus string
+
+const (
+	BulkCreateStatusCreated    BulkCreateStatus = "created"
+	BulkCreateStatusDuplicate  BulkCreateStatus = "duplicate"
+	BulkCreateStatusInvalid    BulkCreateStatus = "invalid"
+	BulkCreateStatusForbidden  BulkCreateStatus = "forbidden"
+	BulkCreateStatusRolledBack BulkCreateStatus = "rolled_back"
+)
+
+// BulkCreateResult is what became of one of the accounts of a bulk create.
+type BulkCreateResult struct {
+	ID     string           `json:"id,omitempty"`
+	Status BulkCreateStatus `json:"status"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+type bulkCreateResultData struct {
+	Data []BulkCreateResult `json:"data"`
+}
+
+type payeeCheckData struct {
+	Data PayeeCheck `json:"data"`
+}
+
+type payeeConfirmationData struct {
+	Data PayeeConfirmation `json:"data"`
+}
+
+type accountUpdate struct {
+	Data accountUpdateData `json:"data"`
+}
+
+type accountUpdateData struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Version    int                    `json:"version"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type Links struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
 }
 
 type Account struct {
@@ -20,27 +123,26 @@ type Account struct {
 	ID             string            `json:"id"`
 	OrganisationID string            `json:"organisation_id"`
 	Version        int               `json:"version"`
+	Deleted        bool              `json:"deleted,omitempty"`
+	Locked         bool              `json:"locked,omitempty"`
 	Attributes     AccountAttributes `json:"attributes"`
 }
 
 type AccountAttributes struct {
 	Country                     string                `json:"country"`
-	BaseCurrency                string                `json:"base_currency"`
-	AccountNumber               string                `json:"account_number"`
-	BankID                      string                `json:"bank_id"`
-	BankIDCode                  string                `json:"bank_id_code"`
-	Bic                         string                `json:"bic"`
-	IBAN                        string                `json:"iban"`
-	Title                       string                `json:"title"`
-	FirstName                   string                `json:"first_name"`
-	BankAccountName             string                `json:"bank_account_name"`
-	AlternativeBankAccountNames []string              `json:"alternative_bank_account_names"`
-	AccountClassification       AccountClassification `json:"account_classification"`
+	BaseCurrency                string                `json:"base_currency,omitempty"`
+	AccountNumber               string                `json:"account_number,omitempty"`
+	BankID                      string                `json:"bank_id,omitempty"`
+	BankIDCode                  string                `json:"bank_id_code,omitempty"`
+	Bic                         string                `json:"bic,omitempty"`
+	IBAN                        string                `json:"iban,omitempty"`
+	CustomerID                  string                `json:"customer_id,omitempty"`
+	Title                       string                `json:"title,omitempty"`
+	FirstName                   string                `json:"first_name,omitempty"`
+	BankAccountName             string                `json:"bank_account_name,omitempty"`
+	AlternativeBankAccountNames []string              `json:"alternative_bank_account_names,omitempty"`
+	AccountClassification       AccountClassification `json:"account_classification,omitempty"`
 	JointAccount                bool                  `json:"joint_account"`
 	AccountMatchingOptOut       bool                  `json:"account_matching_opt_out"`
-	SecondaryIdentification     string                `json:"secondary_identification"`
+	SecondaryIdentification     string                `json:"secondary_identification,omitempty"`
 }
-
-type ErrorResponse struct {
-	Message string `json:"error_message"`
-}
\ No newline at end of file
@@ -0,0 +1,504 @@
+package accountapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	accountsPath       = "/v1/organisation/accounts"
+	contentType        = "application/vnd.api+json"
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// Client talks to the /v1/organisation/accounts routes of the account API.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+}
+
+// NewClient creates a client for the account API served at baseURL, e.g. http://localhost:8080.
+func NewClient(baseURL string) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %q: %v", baseURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q: scheme and host are required", baseURL)
+	}
+	return &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+	}, nil
+}
+
+// WithHTTPClient replaces the *http.Client used to send requests.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// WithRetryPolicy makes the client retry failed requests that are safe to repeat. Requests are
+// not retried by default.
+func (c *Client) WithRetryPolicy(policy RetryPolicy) *Client {
+	c.retryPolicy = policy
+	return c
+}
+
+// ListOptions narrows down the accounts returned by List. Zero values are not sent.
+type ListOptions struct {
+	PageNumber int
+	PageSize   int
+	// Cursor pages through the accounts in the order they were created with the opaque cursors of
+	// the links, rather than page numbers, so that accounts added meanwhile do not shift the pages.
+	// It cannot be combined with PageNumber or Sort.
+	Cursor bool
+	// SkipCount saves the API counting the matching accounts, which leaves out the last link when
+	// paging by number.
+	SkipCount       bool
+	OrganisationIDs []string
+	// Locked, when set, only returns accounts that are locked, or unlocked.
+	Locked *bool
+	// Filter only returns accounts whose attributes have one of the given values, keyed by the
+	// attribute's JSON name, e.g. {"bank_id": {"400300"}, "account_number": {"41426819"}}.
+	Filter map[string][]string
+	// CreatedFrom and CreatedTo only return accounts created at or after, and before, the given times.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// ModifiedFrom and ModifiedTo only return accounts last modified at or after, and before, the given times.
+	ModifiedFrom time.Time
+	ModifiedTo   time.Time
+	// Sort orders the accounts on a comma separated list of fields, each prefixed by "-" for
+	// descending order, e.g. "-created_on,bank_account_name".
+	Sort string
+	// IncludeDeleted also returns deleted accounts, which requires administrator rights.
+	IncludeDeleted bool
+}
+
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+	if o.PageNumber > 0 {
+		values.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+	if o.PageSize > 0 {
+		values.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+	if o.Cursor {
+		values.Set("page[after]", "")
+	}
+	if o.SkipCount {
+		values.Set("page[count]", "false")
+	}
+	for _, id := range o.OrganisationIDs {
+		values.Add("filter[organisation_id]", id)
+	}
+	if o.Locked != nil {
+		values.Set("filter[locked]", strconv.FormatBool(*o.Locked))
+	}
+	for attribute, filter := range o.Filter {
+		for _, value := range filter {
+			values.Add("filter["+attribute+"]", value)
+		}
+	}
+	setTime(values, "filter[created_on_from]", o.CreatedFrom)
+	setTime(values, "filter[created_on_to]", o.CreatedTo)
+	setTime(values, "filter[modified_on_from]", o.ModifiedFrom)
+	setTime(values, "filter[modified_on_to]", o.ModifiedTo)
+	if o.Sort != "" {
+		values.Set("sort", o.Sort)
+	}
+	if o.IncludeDeleted {
+		values.Set("include_deleted", "true")
+	}
+	return values
+}
+
+func setTime(values url.Values, name string, t time.Time) {
+	if !t.IsZero() {
+		values.Set(name, t.Format(time.RFC3339Nano))
+	}
+}
+
+// SearchOptions narrows down the accounts Search looks at. Zero values are not sent.
+type SearchOptions struct {
+	OrganisationIDs         []string
+	BankID                  string
+	AccountNumber           string
+	SecondaryIdentification string
+	// Limit caps the number of matches returned, 20 unless set.
+	Limit int
+}
+
+func (o SearchOptions) values(name string) url.Values {
+	values := url.Values{}
+	values.Set("name", name)
+	for _, id := range o.OrganisationIDs {
+		values.Add("filter[organisation_id]", id)
+	}
+	if o.BankID != "" {
+		values.Set("filter[bank_id]", o.BankID)
+	}
+	if o.AccountNumber != "" {
+		values.Set("filter[account_number]", o.AccountNumber)
+	}
+	if o.SecondaryIdentification != "" {
+		values.Set("filter[secondary_identification]", o.SecondaryIdentification)
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return values
+}
+
+// Fetch returns the account with the given id.
+func (c *Client) Fetch(ctx context.Context, id string) (*Account, error) {
+	result := &AccountData{}
+	err := c.do(ctx, &call{
+		method:         http.MethodGet,
+		url:            c.accountURL(id, nil),
+		expectedStatus: http.StatusOK,
+		result:         result,
+		retryable:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// Create registers a new account and returns it as stored by the API.
+//
+// The request is only retried when the account carries its own ID. If a retry is answered with
+// 409 Conflict because an earlier attempt did reach the server, the stored account is fetched and
+// returned as long as it matches the one being created.
+func (c *Client) Create(ctx context.Context, account Account) (*Account, error) {
+	if account.Type == "" {
+		account.Type = ResourceTypeAccounts
+	}
+	result := &AccountData{}
+	r := &call{
+		method:         http.MethodPost,
+		url:            c.accountsURL(nil),
+		body:           &AccountData{Data: account},
+		expectedStatus: http.StatusCreated,
+		result:         result,
+		retryable:      account.ID != "",
+	}
+	if err := c.do(ctx, r); err != nil {
+		if r.attempts > 1 && errors.Is(err, ErrConflict) {
+			if existing, fetchErr := c.Fetch(ctx, account.ID); fetchErr == nil && SameAccount(account, *existing) {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// CreateMany creates the accounts in one request and returns what became of each of them, in the
+// order they were given. Accounts that cannot be created are reported rather than failing the
+// call, and the others are created, unless atomic is set, in which case none are.
+//
+// The request is only retried when every account carries its own ID. The accounts a retry finds
+// already there are reported as created when they are the accounts that were sent, which an
+// earlier attempt created, and as duplicates otherwise.
+func (c *Client) CreateMany(ctx context.Context, accounts []Account, atomic bool) ([]BulkCreateResult, error) {
+	body := make([]AccountData, len(accounts))
+	retryable := true
+	for i, account := range accounts {
+		if account.Type == "" {
+			account.Type = ResourceTypeAccounts
+		}
+		body[i] = AccountData{Data: account}
+		retryable = retryable && account.ID != ""
+	}
+	query := url.Values{}
+	if atomic {
+		query.Set("atomic", "true")
+	}
+	result := &bulkCreateResultData{}
+	r := &call{
+		method:         http.MethodPost,
+		url:            c.accountURL("bulk", query),
+		body:           body,
+		expectedStatus: http.StatusOK,
+		result:         result,
+		retryable:      retryable,
+	}
+	if err := c.do(ctx, r); err != nil {
+		return nil, err
+	}
+	if r.attempts > 1 {
+		for i, created := range result.Data {
+			if created.Status != BulkCreateStatusDuplicate || i >= len(body) || created.ID != body[i].Data.ID {
+				continue
+			}
+			if existing, fetchErr := c.Fetch(ctx, created.ID); fetchErr == nil && SameAccount(body[i].Data, *existing) {
+				result.Data[i] = BulkCreateResult{ID: created.ID, Status: BulkCreateStatusCreated}
+			}
+		}
+	}
+	return result.Data, nil
+}
+
+// Search returns the accounts whose bank account name, or one of the alternative names, matches
+// name, best match first. Accounts opted out of account matching are never returned, and accounts
+// whose name does not match are only returned when searching for an account number.
+func (c *Client) Search(ctx context.Context, name string, opts SearchOptions) ([]AccountMatch, error) {
+	result := &AccountSearchData{}
+	err := c.do(ctx, &call{
+		method:         http.MethodGet,
+		url:            c.accountURL("search", opts.values(name)),
+		expectedStatus: http.StatusOK,
+		result:         result,
+		retryable:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// ConfirmPayee checks the name a payer gave for the account held under the sort code and account
+// number of the check. The actual name of the account is only given back on a close match.
+func (c *Client) ConfirmPayee(ctx context.Context, check PayeeCheck) (*PayeeConfirmation, error) {
+	result := &payeeConfirmationData{}
+	err := c.do(ctx, &call{
+		method:         http.MethodPost,
+		url:            c.accountURL("confirmation-of-payee", nil),
+		body:           &payeeCheckData{Data: check},
+		expectedStatus: http.StatusOK,
+		result:         result,
+		retryable:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// Update changes the given attributes of the account with the given id, provided it is still at
+// the given version, and returns the account at its new version. Attributes that are left out are
+// not changed, attributes set to nil are cleared. A stale version results in ErrConflict.
+func (c *Client) Update(ctx context.Context, id string, version int, attributes map[string]interface{}) (*Account, error) {
+	result := &AccountData{}
+	err := c.do(ctx, &call{
+		method: http.MethodPatch,
+		url:    c.accountURL(id, nil),
+		body: &accountUpdate{Data: accountUpdateData{
+			ID:         id,
+			Type:       ResourceTypeAccounts,
+			Version:    version,
+			Attributes: attributes,
+		}},
+		expectedStatus: http.StatusOK,
+		result:         result,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// List returns a page of accounts together with the pagination links.
+func (c *Client) List(ctx context.Context, opts ListOptions) (*AccountListData, error) {
+	return c.listPage(ctx, c.accountsURL(opts.values()))
+}
+
+func (c *Client) listPage(ctx context.Context, u string) (*AccountListData, error) {
+	result := &AccountListData{}
+	err := c.do(ctx, &call{
+		method:         http.MethodGet,
+		url:            u,
+		expectedStatus: http.StatusOK,
+		result:         result,
+		retryable:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete marks the account with the given id at the given version as deleted. It is no longer
+// returned by Fetch or List, unless deleted accounts are asked for.
+func (c *Client) Delete(ctx context.Context, id string, version int) error {
+	query := url.Values{}
+	query.Set("version", strconv.Itoa(version))
+	return c.do(ctx, &call{
+		method:         http.MethodDelete,
+		url:            c.accountURL(id, query),
+		expectedStatus: http.StatusNoContent,
+		retryable:      true,
+	})
+}
+
+// Lock freezes the account with the given id at the given version, so that it can no longer be
+// updated or deleted. Updating or deleting a locked account results in ErrLocked.
+func (c *Client) Lock(ctx context.Context, id string, version int) (*Account, error) {
+	return c.changeLock(ctx, id, version, "lock")
+}
+
+// Unlock unfreezes the account with the given id at the given version.
+func (c *Client) Unlock(ctx context.Context, id string, version int) (*Account, error) {
+	return c.changeLock(ctx, id, version, "unlock")
+}
+
+func (c *Client) changeLock(ctx context.Context, id string, version int, action string) (*Account, error) {
+	query := url.Values{}
+	query.Set("version", strconv.Itoa(version))
+	result := &AccountData{}
+	err := c.do(ctx, &call{
+		method:         http.MethodPost,
+		url:            c.accountActionURL(id, action, query),
+		expectedStatus: http.StatusOK,
+		result:         result,
+		retryable:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// SameAccount reports whether stored is the account that was sent, ignoring the attributes the
+// sender left for the API to fill in.
+func SameAccount(sent Account, stored Account) bool {
+	if sent.ID != stored.ID || sent.OrganisationID != stored.OrganisationID || sent.Type != stored.Type {
+		return false
+	}
+	sentAttributes := reflect.ValueOf(sent.Attributes)
+	storedAttributes := reflect.ValueOf(stored.Attributes)
+	for i := 0; i < sentAttributes.NumField(); i++ {
+		field := sentAttributes.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		if !reflect.DeepEqual(field.Interface(), storedAttributes.Field(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) accountsURL(query url.Values) string {
+	u := *c.baseURL
+	u.Path = u.Path + accountsPath
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *Client) accountURL(id string, query url.Values) string {
+	u := *c.baseURL
+	u.Path = u.Path + accountsPath + "/" + url.PathEscape(id)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *Client) accountActionURL(id string, action string, query url.Values) string {
+	u := *c.baseURL
+	u.Path = u.Path + accountsPath + "/" + url.PathEscape(id) + "/" + action
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// resolve turns a link returned by the API, which is a path relative to the host, into an absolute URL.
+func (c *Client) resolve(link string) (string, error) {
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid link %q: %v", link, err)
+	}
+	return c.baseURL.ResolveReference(ref).String(), nil
+}
+
+// call describes one logical request, which may be sent several times when it is retryable.
+type call struct {
+	method         string
+	url            string
+	body           interface{}
+	expectedStatus int
+	result         interface{}
+	retryable      bool
+	attempts       int
+}
+
+func (c *Client) do(ctx context.Context, r *call) error {
+	var payload []byte
+	if r.body != nil {
+		var err error
+		if payload, err = json.Marshal(r.body); err != nil {
+			return fmt.Errorf("could not encode request body: %v", err)
+		}
+	}
+
+	start := time.Now()
+	for {
+		r.attempts++
+		resp, respBody, err := c.send(ctx, r.method, r.url, payload)
+		if err == nil && resp.StatusCode == r.expectedStatus {
+			if r.result == nil {
+				return nil
+			}
+			if err := json.Unmarshal(respBody, r.result); err != nil {
+				return fmt.Errorf("could not decode response body: %v", err)
+			}
+			return nil
+		}
+
+		failure := err
+		if failure == nil {
+			failure = newErrorResponse(resp, respBody)
+		}
+		if !r.retryable || c.retryPolicy == nil || ctx.Err() != nil {
+			return failure
+		}
+		wait, retry := c.retryPolicy.Backoff(r.attempts, time.Since(start), resp, err)
+		if !retry {
+			return failure
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method string, u string, payload []byte) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", contentType)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read response body: %v", err)
+	}
+	return resp, respBody, nil
+}
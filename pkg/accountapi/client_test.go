@@ -0,0 +1,156 @@
+package accountapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeCreateServer answers the first POST with 503, every following POST with 409 and every GET with stored.
+func fakeCreateServer(t *testing.T, stored Account, posts *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			*posts++
+			if *posts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(ErrorResponse{Message: "Account cannot be created as it violates a duplicate constraint"})
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(AccountData{Data: stored})
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client.WithRetryPolicy(NewRetryPolicy(InitialInterval(time.Millisecond)))
+}
+
+func TestClient_Create_ReconcilesConflictAfterRetry(t *testing.T) {
+	account := Account{
+		ID:             "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Attributes:     AccountAttributes{Country: "GB", BankID: "400300"},
+	}
+	stored := account
+	stored.Type = ResourceTypeAccounts
+	stored.Attributes.IBAN = "GB11NWBK40030041426819"
+
+	posts := 0
+	server := fakeCreateServer(t, stored, &posts)
+	defer server.Close()
+
+	created, err := newTestClient(t, server).Create(context.Background(), account)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if posts != 2 {
+		t.Errorf("Create() sent %d requests, want 2", posts)
+	}
+	if created.Attributes.IBAN != stored.Attributes.IBAN {
+		t.Errorf("Create() = %+v, want %+v", created, stored)
+	}
+}
+
+func TestClient_Create_KeepsConflictForDifferentAccount(t *testing.T) {
+	account := Account{
+		ID:             "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Attributes:     AccountAttributes{Country: "GB", BankAccountName: "Samantha Holder"},
+	}
+	stored := account
+	stored.Type = ResourceTypeAccounts
+	stored.Attributes.BankAccountName = "Norman Baker"
+
+	posts := 0
+	server := fakeCreateServer(t, stored, &posts)
+	defer server.Close()
+
+	_, err := newTestClient(t, server).Create(context.Background(), account)
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("Create() error = %v, want %v", err, ErrConflict)
+	}
+}
+
+func TestClient_Create_WithoutIdIsNotRetried(t *testing.T) {
+	posts := 0
+	server := fakeCreateServer(t, Account{}, &posts)
+	defer server.Close()
+
+	_, err := newTestClient(t, server).Create(context.Background(), Account{Attributes: AccountAttributes{Country: "GB"}})
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("Create() error = %v, want %v", err, ErrServer)
+	}
+	if posts != 1 {
+		t.Errorf("Create() sent %d requests, want 1", posts)
+	}
+}
+
+// fakeBulkCreateServer answers the first bulk create with 503, and every following one by
+// reporting each account as a duplicate. It answers every GET with stored.
+func fakeBulkCreateServer(t *testing.T, stored Account, posts *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			*posts++
+			if *posts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var body []AccountData
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("cannot decode bulk create: %v", err)
+			}
+			results := make([]BulkCreateResult, len(body))
+			for i, account := range body {
+				results[i] = BulkCreateResult{ID: account.Data.ID, Status: BulkCreateStatusDuplicate, Reason: "account already exists"}
+			}
+			_ = json.NewEncoder(w).Encode(bulkCreateResultData{Data: results})
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(AccountData{Data: stored})
+		default:
+			t.Errorf("unexpected %s request", r.Method)
+		}
+	}))
+}
+
+func TestClient_CreateMany_ReconcilesDuplicatesAfterRetry(t *testing.T) {
+	account := Account{
+		ID:             "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+		OrganisationID: "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+		Attributes:     AccountAttributes{Country: "GB", BankAccountName: "Samantha Holder"},
+	}
+	stored := account
+	stored.Type = ResourceTypeAccounts
+	other := account
+	other.ID = "7d9f5f0c-3c8f-4b0e-9d44-3bb4c2b5c8a1"
+
+	posts := 0
+	server := fakeBulkCreateServer(t, stored, &posts)
+	defer server.Close()
+
+	results, err := newTestClient(t, server).CreateMany(context.Background(), []Account{account, other}, false)
+	if err != nil {
+		t.Fatalf("CreateMany() error = %v", err)
+	}
+	want := []BulkCreateResult{
+		{ID: account.ID, Status: BulkCreateStatusCreated},
+		{ID: other.ID, Status: BulkCreateStatusDuplicate, Reason: "account already exists"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("CreateMany() = %+v, want %+v", results, want)
+	}
+}
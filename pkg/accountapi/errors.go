@@ -0,0 +1,68 @@
+package accountapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// Sentinel errors matched by ErrorResponse, so callers can use errors.Is on any error returned by the Client.
+var (
+	ErrNotFound   = errors.New("account api: not found")
+	ErrConflict   = errors.New("account api: conflict")
+	ErrValidation = errors.New("account api: validation failed")
+	ErrForbidden  = errors.New("account api: forbidden")
+	ErrLocked     = errors.New("account api: account locked")
+	ErrServer     = errors.New("account api: server error")
+)
+
+// ErrorResponse is returned for every response with an unexpected status code.
+// Use errors.As to get at the status code, server message and request ID.
+type ErrorResponse struct {
+	StatusCode int    `json:"-"`
+	RequestID  string `json:"-"`
+	Message    string `json:"error_message"`
+}
+
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("account api responded with status %d", e.StatusCode)
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if e.RequestID != "" {
+		msg = fmt.Sprintf("%s (request id %s)", msg, e.RequestID)
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error matching the status code, or nil if there is none.
+func (e *ErrorResponse) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrValidation
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return ErrForbidden
+	case e.StatusCode == http.StatusLocked:
+		return ErrLocked
+	case e.StatusCode >= http.StatusInternalServerError:
+		return ErrServer
+	}
+	return nil
+}
+
+func newErrorResponse(resp *http.Response, body []byte) *ErrorResponse {
+	e := &ErrorResponse{}
+	if err := json.Unmarshal(body, e); err != nil {
+		e.Message = string(body)
+	}
+	e.StatusCode = resp.StatusCode
+	e.RequestID = resp.Header.Get(requestIDHeader)
+	return e
+}
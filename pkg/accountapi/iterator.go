@@ -0,0 +1,102 @@
+package accountapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListIterator walks through all accounts matching a set of ListOptions, following the
+// links.next URL returned by the API. Pages are only fetched once the previous one is used up.
+//
+//	it := client.Iterate(ctx, accountapi.ListOptions{PageSize: 100})
+//	for it.Next() {
+//		account := it.Account()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ListIterator struct {
+	ctx     context.Context
+	client  *Client
+	nextURL string
+	visited map[string]bool
+	page    []Account
+	current Account
+	err     error
+}
+
+// Iterate returns a ListIterator starting at the page described by opts.
+func (c *Client) Iterate(ctx context.Context, opts ListOptions) *ListIterator {
+	return &ListIterator{
+		ctx:     ctx,
+		client:  c,
+		nextURL: c.accountsURL(opts.values()),
+		visited: map[string]bool{},
+	}
+}
+
+// Next advances to the next account, fetching the next page when needed. It returns false when
+// there are no more accounts, the context is done or a request failed; check Err to tell them apart.
+func (it *ListIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	for len(it.page) == 0 {
+		if it.nextURL == "" {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Account returns the account Next advanced to.
+func (it *ListIterator) Account() Account {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ListIterator) Err() error {
+	return it.err
+}
+
+// Collect drains the iterator into a slice, stopping after max accounts. A max of zero or less
+// collects everything. The accounts read before an error are returned along with it.
+func (it *ListIterator) Collect(max int) ([]Account, error) {
+	var accounts []Account
+	for (max <= 0 || len(accounts) < max) && it.Next() {
+		accounts = append(accounts, it.Account())
+	}
+	return accounts, it.Err()
+}
+
+func (it *ListIterator) fetch() error {
+	u := it.nextURL
+	if it.visited[u] {
+		return fmt.Errorf("pagination loop detected, %s was already fetched", u)
+	}
+	it.visited[u] = true
+
+	result, err := it.client.listPage(it.ctx, u)
+	if err != nil {
+		return err
+	}
+
+	it.page = result.Data
+	it.nextURL = ""
+	if result.Links != nil && result.Links.Next != "" {
+		if it.nextURL, err = it.client.resolve(result.Links.Next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
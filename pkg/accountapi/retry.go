@@ -0,0 +1,181 @@
+package accountapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxTries        = 5
+	DefaultInitialInterval = 100 * time.Millisecond
+	DefaultMaxInterval     = 5 * time.Second
+	DefaultMultiplier      = 2.0
+	DefaultJitter          = 0.5
+	DefaultMaxElapsedTime  = 30 * time.Second
+)
+
+// RetryPolicy decides whether a failed attempt is sent again. Backoff is called after every
+// failed attempt with either the response (body already read) or the transport error, and
+// returns how long to wait before the next attempt, or false to give up.
+//
+// The Client only consults the policy for requests that are safe to repeat: GET, DELETE with a
+// version, locking and unlocking, and POST of an account carrying its own ID. PATCH is never
+// retried, since a repeated update is rejected once the first one has moved the account to a new
+// version.
+type RetryPolicy interface {
+	Backoff(attempt int, elapsed time.Duration, resp *http.Response, err error) (time.Duration, bool)
+}
+
+type RetryOption func(b *ExponentialBackoff)
+
+// MaxTries is the maximum number of attempts, including the first one.
+func MaxTries(tries int) RetryOption {
+	return func(b *ExponentialBackoff) {
+		b.MaxTries = tries
+	}
+}
+
+// InitialInterval is the wait after the first failed attempt.
+func InitialInterval(d time.Duration) RetryOption {
+	return func(b *ExponentialBackoff) {
+		b.InitialInterval = d
+	}
+}
+
+// MaxInterval caps the wait between two attempts, before jitter is applied.
+func MaxInterval(d time.Duration) RetryOption {
+	return func(b *ExponentialBackoff) {
+		b.MaxInterval = d
+	}
+}
+
+// Multiplier is the factor the wait grows by after each failed attempt.
+func Multiplier(m float64) RetryOption {
+	return func(b *ExponentialBackoff) {
+		b.Multiplier = m
+	}
+}
+
+// Jitter randomises each wait by up to the given fraction in either direction, e.g. 0.5 for ±50%.
+func Jitter(fraction float64) RetryOption {
+	return func(b *ExponentialBackoff) {
+		b.Jitter = fraction
+	}
+}
+
+// MaxElapsedTime stops retrying once the next attempt would start later than this after the first one.
+func MaxElapsedTime(d time.Duration) RetryOption {
+	return func(b *ExponentialBackoff) {
+		b.MaxElapsedTime = d
+	}
+}
+
+// ExponentialBackoff retries transient failures with an exponentially growing, jittered wait,
+// honouring the Retry-After header when the server sends one.
+type ExponentialBackoff struct {
+	MaxTries        int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+	MaxElapsedTime  time.Duration
+}
+
+// NewRetryPolicy returns an ExponentialBackoff using the defaults overridden by options.
+func NewRetryPolicy(options ...RetryOption) *ExponentialBackoff {
+	b := &ExponentialBackoff{
+		MaxTries:        DefaultMaxTries,
+		InitialInterval: DefaultInitialInterval,
+		MaxInterval:     DefaultMaxInterval,
+		Multiplier:      DefaultMultiplier,
+		Jitter:          DefaultJitter,
+		MaxElapsedTime:  DefaultMaxElapsedTime,
+	}
+	for _, option := range options {
+		option(b)
+	}
+	return b
+}
+
+func (b *ExponentialBackoff) Backoff(attempt int, elapsed time.Duration, resp *http.Response, err error) (time.Duration, bool) {
+	if !IsTransient(resp, err) {
+		return 0, false
+	}
+	if b.MaxTries > 0 && attempt >= b.MaxTries {
+		return 0, false
+	}
+
+	wait, ok := retryAfter(resp)
+	if !ok {
+		wait = b.interval(attempt)
+	}
+
+	if b.MaxElapsedTime > 0 && elapsed+wait > b.MaxElapsedTime {
+		return 0, false
+	}
+	return wait, true
+}
+
+func (b *ExponentialBackoff) interval(attempt int) time.Duration {
+	interval := float64(b.InitialInterval) * math.Pow(b.Multiplier, float64(attempt-1))
+	if b.MaxInterval > 0 && interval > float64(b.MaxInterval) {
+		interval = float64(b.MaxInterval)
+	}
+	if b.Jitter > 0 {
+		interval = interval * (1 + b.Jitter*(2*rand.Float64()-1))
+	}
+	return time.Duration(interval)
+}
+
+// IsTransient reports whether a failed attempt is worth repeating: transport errors other than
+// the context being done, 429 Too Many Requests and 5xx responses other than 501 Not Implemented.
+func IsTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented
+}
+
+// retryAfter reads the Retry-After header, given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
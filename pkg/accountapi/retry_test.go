@@ -0,0 +1,64 @@
+package accountapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff_Backoff(t *testing.T) {
+	policy := NewRetryPolicy(
+		MaxTries(4),
+		InitialInterval(100*time.Millisecond),
+		MaxInterval(300*time.Millisecond),
+		Jitter(0),
+		MaxElapsedTime(time.Second),
+	)
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name      string
+		attempt   int
+		elapsed   time.Duration
+		resp      *http.Response
+		err       error
+		wantWait  time.Duration
+		wantRetry bool
+	}{
+		{"connection error", 1, 0, nil, errors.New("connection reset by peer"), 100 * time.Millisecond, true},
+		{"service unavailable", 2, 0, response(503, ""), nil, 200 * time.Millisecond, true},
+		{"capped at max interval", 3, 0, response(500, ""), nil, 300 * time.Millisecond, true},
+		{"too many requests with retry after", 1, 0, response(429, "1"), nil, time.Second, true},
+		{"max tries reached", 4, 0, response(503, ""), nil, 0, false},
+		{"max elapsed time reached", 1, 950 * time.Millisecond, response(503, ""), nil, 0, false},
+		{"retry after beyond max elapsed time", 1, 0, response(503, "2"), nil, 0, false},
+		{"not implemented", 1, 0, response(501, ""), nil, 0, false},
+		{"conflict", 1, 0, response(409, ""), nil, 0, false},
+		{"not found", 1, 0, response(404, ""), nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retry := policy.Backoff(tt.attempt, tt.elapsed, tt.resp, tt.err)
+			if retry != tt.wantRetry || wait != tt.wantWait {
+				t.Errorf("Backoff() = (%v, %v), want (%v, %v)", wait, retry, tt.wantWait, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff_Jitter(t *testing.T) {
+	policy := NewRetryPolicy(InitialInterval(100*time.Millisecond), Jitter(0.5))
+	for i := 0; i < 100; i++ {
+		wait, retry := policy.Backoff(1, 0, &http.Response{StatusCode: 503}, nil)
+		if !retry || wait < 50*time.Millisecond || wait > 150*time.Millisecond {
+			t.Fatalf("Backoff() = (%v, %v), want a wait between 50ms and 150ms", wait, retry)
+		}
+	}
+}